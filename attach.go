@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// attachPollRate is how often --attach asks the running instance for its state.
+const attachPollRate = 250 * time.Millisecond
+
+// attachState is the timer state sent over the control socket for --attach.
+type attachState struct {
+	State     string        `json:"state"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Total     time.Duration `json:"total"`
+	Label     string        `json:"label,omitempty"`
+	Stopwatch bool          `json:"stopwatch,omitempty"`
+	WaitUntil time.Time     `json:"wait_until,omitzero"`
+}
+
+// stateJSON encodes the timer state for an attached display.
+func (m model) stateJSON() string {
+	data, _ := json.Marshal(attachState{
+		State:     m.stateName(),
+		Elapsed:   m.elapsedTime,
+		Total:     m.totalTime,
+		Label:     m.label,
+		Stopwatch: m.opts.stopwatch,
+		WaitUntil: m.waitUntil,
+	})
+	return string(data)
+}
+
+// attachPollMsg carries the running instance's state, or why it could not be read.
+type attachPollMsg struct {
+	state attachState
+	err   error
+}
+
+// attachPollCmd asks the running instance for its state after delay.
+func attachPollCmd(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
+		reply, err := queryControl(stateCommand)
+		if err != nil {
+			return attachPollMsg{err: err}
+		}
+		var state attachState
+		err = json.Unmarshal([]byte(reply), &state)
+		return attachPollMsg{state: state, err: err}
+	})
+}
+
+// attachModel shows another instance's timer read-only (--attach). It draws
+// through a mirror model kept in step with the polled state.
+type attachModel struct {
+	mirror model
+	err    error // Why the display was closed, if the instance went away
+}
+
+// Init polls right away.
+func (a attachModel) Init() tea.Cmd {
+	return attachPollCmd(0)
+}
+
+// Update applies polled state; q or Ctrl+C detaches.
+func (a attachModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC || (msg.String() == "q" && !msg.Paste) {
+			return a, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		a.mirror.termWidth = msg.Width
+		a.mirror.termHeight = msg.Height
+	case attachPollMsg:
+		if msg.err != nil {
+			a.err = msg.err
+			return a, tea.Quit
+		}
+		a.mirror.show(msg.state)
+		return a, attachPollCmd(attachPollRate)
+	}
+	return a, nil
+}
+
+// View draws the mirrored timer with a read-only note in place of the controls.
+func (a attachModel) View() string {
+	if a.mirror.totalTime == 0 {
+		return "" // Not polled yet
+	}
+	return a.mirror.View() + "\n    " + centerText("read-only · q to detach")
+}
+
+// show sets the mirror's fields from a polled state, matching how the running
+// instance represents each state name.
+func (m *model) show(s attachState) {
+	m.opts.stopwatch = s.Stopwatch
+	m.opts.hideControls = true
+	m.totalTime = s.Total
+	m.elapsedTime = s.Elapsed
+	m.label = s.Label
+	m.waitUntil = s.WaitUntil
+	m.isRunning = s.State == "running" || s.State == "paused" || s.State == "pausing" || s.State == "resuming"
+	m.isPaused = s.State == "paused" || s.State == "resuming"
+	m.inOvertime = s.State == "overtime"
+	m.blink = s.State != "finished" || time.Now().UnixMilli()/blinkRate.Milliseconds()%2 == 0 // Blink as the original does
+}
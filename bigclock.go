@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bigGlyphs are the 5-row block characters of the big clock.
+var bigGlyphs = map[rune][]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {"   ", " █ ", "   ", " █ ", "   "},
+}
+
+// bigClock draws timer in block digits, centered in the donut's 13x29 area
+// so the layout does not jump when the donut takes over (--clock-then-donut).
+func bigClock(timer string, style lipgloss.Style) string {
+	rows := make([]string, len(bigGlyphs['0']))
+	for i, r := range timer {
+		glyph, ok := bigGlyphs[r]
+		if !ok {
+			glyph = []string{"   ", "   ", "   ", "   ", "   "} // A gap for anything without a glyph
+		}
+		for y := range rows {
+			if i > 0 {
+				rows[y] += " "
+			}
+			rows[y] += glyph[y]
+		}
+	}
+	lines := make([]string, height)
+	top := (height - len(rows)) / 2
+	for y, row := range rows {
+		lines[top+y] = strings.Repeat(" ", max(0, (width-lipgloss.Width(row))/2)) + style.Render(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// showBigClock reports whether View draws the big clock instead of the donut:
+// with --clock-then-donut, until the timer is first started.
+func (m model) showBigClock() bool {
+	return m.opts.clockThenDonut && !m.isRunning && m.elapsedTime == 0
+}
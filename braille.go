@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+)
+
+// Ring radii for --braille, in dots. A cell holds 2x4 dots, which are about
+// square on a typical terminal, so the 13x29 donut is a 58x52 dot grid.
+const (
+	brailleOuter = 25.5
+	brailleInner = 17.0
+)
+
+// brailleBits maps a dot's column and row within a cell to its bit in the
+// Unicode Braille Patterns block.
+var brailleBits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// brailleCells lays out the donut with Braille dots (--braille), giving a much
+// smoother ring than one glyph per cell. A glyph has a single color, so each
+// cell takes the elapsed or remaining color from the position of its center.
+func brailleCells(progress float64, timer string, ring ringOptions) [][]cell {
+	centerX, centerY := float64(width), float64(height)*2 // In dots
+	elapsedStyle, remainingStyle := whiteStyle, redStyle
+	if ring.paused {
+		elapsedStyle, remainingStyle = dimWhiteStyle, dimRedStyle
+	}
+	digits := timerCells(timer)
+	timerStart := (width - len(digits)) / 2
+	inner := brailleOuter - (brailleOuter-brailleInner)*ringThickness(ring.breath) // Thinner on the --breathe exhale
+
+	cells := make([][]cell, height)
+	for y := range cells {
+		cells[y] = make([]cell, width)
+		for x := range cells[y] {
+			if y == height/2 && x >= timerStart && x < timerStart+len(digits) {
+				cells[y][x] = cell{digits[x-timerStart], ring.timerStyle, true}
+				continue
+			}
+			var glyph rune
+			for r := range 4 {
+				for c := range 2 {
+					dx := float64(x*2+c) + 0.5 - centerX
+					dy := float64(y*4+r) + 0.5 - centerY
+					if d := math.Hypot(dx, dy); d >= inner && d <= brailleOuter {
+						glyph |= brailleBits[r][c]
+					}
+				}
+			}
+			if glyph == 0 {
+				cells[y][x] = cell{char: " "}
+				continue
+			}
+			pos := ringPosition(float64(x*2+1)-centerX, float64(y*4+2)-centerY)
+			style := remainingStyle
+			if ring.animLit(pos) {
+				style = highlightStyle
+			} else if pos < progress {
+				style = elapsedStyle
+			}
+			cells[y][x] = cell{string(0x2800 + glyph), style, true}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// breathMin is the share of the ring's depth still drawn when fully breathed
+// out with --breathe, so the ring never vanishes.
+const breathMin = 0.35
+
+// donutDepth is the depth of the ASCII donut's ring as a share of its outer
+// radius, measured on the template scaled to a unit circle.
+const donutDepth = 0.55
+
+// breathCycle is the --breathe-cycle rhythm.
+type breathCycle struct {
+	in, out time.Duration
+}
+
+// parseBreathCycle parses "inhale,exhale", e.g. "4s,4s" or "4s,6s".
+func parseBreathCycle(spec string) (breathCycle, error) {
+	in, out, ok := strings.Cut(spec, ",")
+	if !ok {
+		return breathCycle{}, fmt.Errorf("invalid --breathe-cycle %q, expected inhale,exhale e.g. 4s,4s", spec)
+	}
+	var c breathCycle
+	var err error
+	if c.in, err = time.ParseDuration(strings.TrimSpace(in)); err != nil || c.in <= 0 {
+		return breathCycle{}, fmt.Errorf("invalid --breathe-cycle inhale time %q", in)
+	}
+	if c.out, err = time.ParseDuration(strings.TrimSpace(out)); err != nil || c.out <= 0 {
+		return breathCycle{}, fmt.Errorf("invalid --breathe-cycle exhale time %q", out)
+	}
+	return c, nil
+}
+
+// breath returns how full the breath is at elapsed, from 0 (out) to 1 (in),
+// easing at both ends, and whether it is the inhale half of the cycle.
+func (c breathCycle) breath(elapsed time.Duration) (level float64, inhaling bool) {
+	t := elapsed % (c.in + c.out)
+	if t < c.in {
+		return (1 - math.Cos(math.Pi*float64(t)/float64(c.in))) / 2, true
+	}
+	t -= c.in
+	return (1 + math.Cos(math.Pi*float64(t)/float64(c.out))) / 2, false
+}
+
+// breathLevel returns the ring fullness for the current moment, or -1 when
+// the ring should not breathe: without --breathe, or while stopped or paused.
+func (m model) breathLevel() float64 {
+	if !m.opts.breathe || !m.isRunning || m.isPaused || m.inOvertime {
+		return -1
+	}
+	level, _ := m.opts.breathCycle.breath(m.elapsedTime)
+	return level
+}
+
+// breathText is the inhale or exhale cue shown in the status line while the timer runs.
+func (m model) breathText() string {
+	if _, inhaling := m.opts.breathCycle.breath(m.elapsedTime); inhaling {
+		return msgs.Inhale
+	}
+	return msgs.Exhale
+}
+
+// ringThickness returns the share of the ring's depth, from the outer edge,
+// drawn at breath level b: all of it breathed in, a thin band breathed out.
+// Negative b draws all of it.
+func ringThickness(b float64) float64 {
+	if b < 0 {
+		return 1
+	}
+	return breathMin + (1-breathMin)*b
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxCategories is how many --categories the number keys 1-9 can reach.
+const maxCategories = 9
+
+// parseCategories splits the --categories list, e.g. "work,study,admin".
+func parseCategories(list string) ([]string, error) {
+	var categories []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("empty category in --categories %q", list)
+		}
+		categories = append(categories, name)
+	}
+	if len(categories) > maxCategories {
+		return nil, fmt.Errorf("--categories has %d names, the keys 1-%d allow at most %d", len(categories), maxCategories, maxCategories)
+	}
+	return categories, nil
+}
+
+// setCategory tags the current session with the category on number key n
+// (1-based), or clears the tag if it already has that category.
+func (m model) setCategory(n int) (tea.Model, tea.Cmd) {
+	if n > len(m.opts.categories) {
+		return m.flashError(fmt.Sprintf("no category %d", n))
+	}
+	name := m.opts.categories[n-1]
+	if m.category == name {
+		m.category = ""
+		m.flashText = "category cleared"
+	} else {
+		m.category = name
+		m.flashText = truncateText("category: "+name, width)
+	}
+	m.flashUntil = time.Now().Add(flashDuration)
+	return m, tea.Tick(flashDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+}
+
+// categoryBadge is the category tag shown after the label, e.g. "[work]".
+func (m model) categoryBadge() string {
+	if m.category == "" {
+		return ""
+	}
+	return "[" + m.category + "]"
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copiedMsg reports the outcome of a clipboard copy.
+type copiedMsg struct {
+	err error
+}
+
+// clipboardCommands lists the clipboard tools tried, in order, for each platform.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	},
+}
+
+// copyToClipboard returns a command that writes text to the system clipboard
+// using the first available clipboard tool.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		for _, args := range clipboardCommands[runtime.GOOS] {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				continue
+			}
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			return copiedMsg{err: cmd.Run()}
+		}
+		return copiedMsg{err: errors.New("no clipboard tool available")}
+	}
+}
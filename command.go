@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxCommandInput caps the length of a ':' command line.
+const maxCommandInput = 40
+
+// editCommand handles a key while the ':' command line is open: typing,
+// Backspace, Esc to cancel and Enter to run the command.
+func (m model) editCommand(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.commanding = false
+		m.commandLine = ""
+	case tea.KeyEnter:
+		line := strings.TrimSpace(m.commandLine)
+		m.commanding = false
+		m.commandLine = ""
+		if line != "" {
+			return m.runCommand(line)
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.commandLine); len(runes) > 0 {
+			m.commandLine = string(runes[:len(runes)-1])
+		} else {
+			m.commanding = false // Backspace past the ':' closes the line, as in vim
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(m.commandLine))+len(key.Runes) <= maxCommandInput {
+			m.commandLine += string(key.Runes)
+		}
+	}
+	return m, nil
+}
+
+// runCommand applies a ':' command:
+//
+//	set mm:ss     change the length of the current timer, keeping the time already elapsed
+//	label TEXT    rename the current timer (an empty label removes it)
+//	pause, resume, reset, quit
+//
+// Errors are flashed in the status area.
+func (m model) runCommand(line string) (tea.Model, tea.Cmd) {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	key := func(k string) (tea.Model, tea.Cmd) {
+		return m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+
+	switch name {
+	case "set":
+		if m.opts.stopwatch {
+			return m.flashError("set: not available in stopwatch mode")
+		}
+		duration, err := parseDuration(arg)
+		if err != nil {
+			return m.flashError("set: " + err.Error())
+		}
+		m.totalTime = duration
+		if m.plan != nil {
+			m.plan[m.planIndex].duration = duration
+		}
+		if !m.isRunning && !m.inOvertime && m.elapsedTime < duration {
+			m.isRunning = true // A finished timer that got longer carries on
+			m.startTime = time.Now().Add(-m.elapsedTime)
+			return m, tea.Batch(m.startTick(), m.completeCmd())
+		}
+		return m, m.completeCmd() // Reschedule the end for the new length
+	case "label":
+		m.label = arg
+	case "pause":
+		if m.isRunning && !m.isPaused {
+			return key("p")
+		}
+	case "resume":
+		if m.isPaused {
+			return key("p")
+		}
+	case "reset":
+		return key("r")
+	case "quit":
+		return key("q")
+	default:
+		return m.flashError("unknown command: " + name)
+	}
+	return m, nil
+}
+
+// flashError shows msg in the status area for a moment.
+func (m model) flashError(msg string) (tea.Model, tea.Cmd) {
+	m.flashText = truncateText(msg, width)
+	m.flashUntil = time.Now().Add(2 * flashDuration)
+	return m, tea.Tick(2*flashDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+}
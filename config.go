@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// configKey matches a bare TOML key; keys are the long flag names, e.g. theme or min-log.
+var configKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// configEntry is one key = value setting read from the config file.
+type configEntry struct {
+	line  int
+	key   string
+	value string // Unquoted, ready for flag.Set
+}
+
+// configError reports a problem in the config file, with its location when known.
+type configError struct {
+	path string
+	line int
+	msg  string
+}
+
+func (e *configError) Error() string {
+	if e.line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.path, e.line, e.msg)
+	}
+	return fmt.Sprintf("%s: %s", e.path, e.msg)
+}
+
+// configEnv names the environment variable that overrides the config file location.
+const configEnv = "GOPOMOTIME_CONFIG"
+
+// configPath returns the config file to read and where that choice came from,
+// in order of precedence: $GOPOMOTIME_CONFIG, $XDG_CONFIG_HOME/gopomotime/config.toml,
+// then the platform's user config directory. The path is "" when none is available.
+func configPath() (path, source string) {
+	if p := os.Getenv(configEnv); p != "" {
+		return p, "$" + configEnv
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gopomotime", "config.toml"), "$XDG_CONFIG_HOME"
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", ""
+	}
+	return filepath.Join(dir, "gopomotime", "config.toml"), "user config directory"
+}
+
+// parseConfig reads the flat subset of TOML used by the config file: one
+// key = value per line, where a value is a string, boolean or number, and
+// # starts a comment. A missing file is only an error when it was asked for
+// explicitly with required.
+func parseConfig(path string, required bool) ([]configEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			err = pe.Err // configError already names the file
+		}
+		return nil, &configError{path: path, msg: err.Error()}
+	}
+	defer f.Close()
+
+	var entries []configEntry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return nil, &configError{path, n, "tables are not supported; put settings at the top level"}
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, &configError{path, n, "expected key = value"}
+		}
+		key = strings.TrimSpace(key)
+		if !configKey.MatchString(key) {
+			return nil, &configError{path, n, fmt.Sprintf("invalid key %q", key)}
+		}
+		value, err := parseConfigValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, &configError{path, n, fmt.Sprintf("%s: %v", key, err)}
+		}
+		entries = append(entries, configEntry{line: n, key: key, value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &configError{path: path, msg: err.Error()}
+	}
+	return entries, nil
+}
+
+// parseConfigValue decodes a TOML string, boolean or number, dropping any trailing comment.
+func parseConfigValue(raw string) (string, error) {
+	if raw == "" {
+		return "", errors.New("missing value")
+	}
+	switch quote := raw[0]; quote {
+	case '"', '\'':
+		// Find the closing quote, skipping backslash escapes in basic strings
+		end := 0
+		for i := 1; i < len(raw); i++ {
+			if quote == '"' && raw[i] == '\\' {
+				i++
+				continue
+			}
+			if raw[i] == quote {
+				end = i
+				break
+			}
+		}
+		if end == 0 {
+			return "", errors.New("unterminated string")
+		}
+		if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after string", rest)
+		}
+		if quote == '\'' {
+			return raw[1:end], nil // Literal strings have no escapes
+		}
+		s, err := strconv.Unquote(raw[:end+1])
+		if err != nil {
+			return "", errors.New("invalid escape in string")
+		}
+		return s, nil
+	}
+	value, _, _ := strings.Cut(raw, "#")
+	value = strings.TrimSpace(value)
+	if value == "true" || value == "false" {
+		return value, nil
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64); err != nil {
+		return "", fmt.Errorf("%q is not a string, boolean or number (quote strings)", value)
+	}
+	return strings.ReplaceAll(value, "_", ""), nil
+}
+
+// applyConfig sets flags from the config file at path, leaving any flag given
+// on the command line alone. Nothing is applied if the file has an error.
+func applyConfig(path string, required bool) error {
+	entries, err := parseConfig(path, required)
+	if err != nil {
+		return err
+	}
+
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	// Check every entry before setting any, so a bad file leaves the defaults intact
+	for _, e := range entries {
+		if strings.HasPrefix(e.key, presetPrefix) {
+			continue // Checked once the settings are in, as max-minutes affects them
+		}
+		if flag.Lookup(e.key) == nil || e.key == "version" || e.key == "strict-config" {
+			return &configError{path, e.line, fmt.Sprintf("unknown setting %q", e.key)}
+		}
+	}
+	var undo []func()
+	rollback := func() {
+		// Put back what was already applied, newest first
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+	for _, e := range entries {
+		if explicit[e.key] || strings.HasPrefix(e.key, presetPrefix) {
+			continue
+		}
+		old := flag.Lookup(e.key).Value.String()
+		if err := flag.Set(e.key, e.value); err != nil {
+			rollback()
+			return &configError{path, e.line, fmt.Sprintf("%s: %v", e.key, err)}
+		}
+		undo = append(undo, func() { flag.Set(e.key, old) })
+	}
+	for _, e := range entries {
+		if name, ok := strings.CutPrefix(e.key, presetPrefix); ok {
+			if _, err := parsePreset(name, e.value); err != nil {
+				rollback()
+				return &configError{path, e.line, fmt.Sprintf("%s: %v", e.key, err)}
+			}
+		}
+	}
+	return nil
+}
+
+// saveSetting records key = value in the config file, replacing an existing
+// line for key, so a choice made in the app sticks. It only updates a config
+// file that already exists, leaving users without one unaffected.
+func saveSetting(key, value string) error {
+	path, _ := configPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	setting := key + " = " + value
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	found := false
+	for i, line := range lines {
+		if k, _, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == key && !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines[i] = setting
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, setting)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ctlTimeout bounds how long `gopomotime ctl` waits for the running instance.
+const ctlTimeout = 2 * time.Second
+
+// ctlCommands are the commands accepted on the control socket.
+var ctlCommands = map[string]bool{"pause": true, "resume": true, "reset": true, "status": true}
+
+// stateCommand asks for the full timer state as JSON, for --attach. It is
+// accepted on the socket but not offered by `gopomotime ctl`.
+const stateCommand = "state"
+
+// quitCommand asks the running instance to log its session and exit, for
+// --on-existing replace. Like stateCommand it is not offered by `gopomotime ctl`.
+const quitCommand = "quit"
+
+// controlMsg carries a command from the control socket into Update. The reply
+// channel is buffered so Update never blocks on it.
+type controlMsg struct {
+	command string
+	reply   chan string
+}
+
+// controlSocketPath returns where the running instance listens, in
+// $XDG_RUNTIME_DIR when set and the temp directory otherwise.
+func controlSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gopomotime.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gopomotime-%d.sock", os.Getuid()))
+}
+
+// listenControl opens the control socket. A socket left behind by an instance
+// that has exited is replaced; one owned by a live instance is an error.
+func listenControl(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, ctlTimeout); err == nil {
+		conn.Close()
+		return nil, errors.New("another gopomotime is already running")
+	}
+	os.Remove(path) // Stale socket from a crash
+	return net.Listen("unix", path)
+}
+
+// serveControl answers one command per connection, forwarding each to the
+// program and writing back its reply, until ln is closed.
+func serveControl(ln net.Listener, p *tea.Program) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(ctlTimeout))
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			if reply, ok := forwardControl(p, line); ok {
+				fmt.Fprintln(conn, reply)
+			}
+		}()
+	}
+}
+
+// forwardControl sends one command line to the program and returns its reply,
+// or an "error: " reply for an unknown command. ok is false if the program
+// did not answer in time.
+func forwardControl(p *tea.Program, line string) (reply string, ok bool) {
+	command := strings.TrimSpace(line)
+	if !ctlCommands[command] && command != stateCommand && command != quitCommand {
+		return fmt.Sprintf("error: unknown command %q", command), true
+	}
+	replies := make(chan string, 1)
+	p.Send(controlMsg{command: command, reply: replies})
+	select {
+	case reply = <-replies:
+		return reply, true
+	case <-time.After(ctlTimeout):
+		return "", false
+	}
+}
+
+// openControlFD opens the file descriptor given with --control-fd, checking
+// that it is open.
+func openControlFD(fd int) (*os.File, error) {
+	if fd < 0 {
+		return nil, fmt.Errorf("--control-fd %d is not a valid file descriptor", fd)
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("--control-fd %d is not open", fd)
+	}
+	return f, nil
+}
+
+// serveControlFD reads commands from f one per line, as on the control socket,
+// writing each reply back to f when it is writable (e.g. a socketpair). It
+// returns when f reaches EOF; a read error is shown in the status line.
+func serveControlFD(f *os.File, p *tea.Program) {
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		if reply, ok := forwardControl(p, scanner.Text()); ok {
+			fmt.Fprintln(f, reply) // A read-only pipe just refuses the write
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		p.Send(controlFDErrMsg{err})
+	}
+}
+
+// controlFDErrMsg reports that --control-fd could not be read.
+type controlFDErrMsg struct {
+	err error
+}
+
+// handleControl applies a control command by replaying the matching key, so
+// it behaves exactly as pressing it would (including --strict), and returns
+// the status after the command.
+func (m model) handleControl(msg controlMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	key := func(k string) {
+		var next tea.Model
+		next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = next.(model)
+	}
+	switch msg.command {
+	case "pause":
+		if m.isRunning && !m.isPaused && !m.pendingPauseToggle {
+			key("p")
+		}
+	case "resume":
+		if (m.isPaused || (!m.isRunning && m.elapsedTime < m.totalTime)) && !m.pendingPauseToggle && !m.inOvertime {
+			key("p")
+		}
+	case "reset":
+		key("r")
+	case stateCommand:
+		msg.reply <- m.stateJSON()
+		return m, nil
+	case quitCommand:
+		if m.isRunning && !m.isPaused {
+			m.elapsedTime = time.Since(m.startTime) // Account up to the moment of quitting
+		}
+		m.logSession()
+		msg.reply <- m.statusText()
+		return m, tea.Quit
+	}
+	msg.reply <- m.statusText()
+	return m, cmd
+}
+
+// statusText describes the timer in one line for `gopomotime ctl status`.
+func (m model) statusText() string {
+	text := m.stateName() + " " + m.timerText()
+	if m.label != "" {
+		text += " " + m.label
+	}
+	return text
+}
+
+// stateName names what the timer is doing, e.g. "running" or "paused".
+func (m model) stateName() string {
+	state := "running"
+	switch {
+	case !m.waitUntil.IsZero():
+		state = "waiting"
+	case m.inOvertime:
+		state = "overtime"
+	case m.pendingPauseToggle && m.isPaused:
+		state = "resuming"
+	case m.pendingPauseToggle:
+		state = "pausing"
+	case m.isPaused:
+		state = "paused"
+	case !m.isRunning && m.elapsedTime >= m.totalTime:
+		state = "finished"
+	case !m.isRunning:
+		state = "stopped"
+	}
+	return state
+}
+
+// runCtl implements `gopomotime ctl pause|resume|reset|status`, sending the
+// command to the running instance and printing its status. It returns the exit code.
+func runCtl(args []string) int {
+	if len(args) != 1 || !ctlCommands[args[0]] {
+		fmt.Fprintln(os.Stderr, "Usage: gopomotime ctl pause|resume|reset|status")
+		return 2
+	}
+	reply, err := queryControl(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Println(reply)
+	return 0
+}
+
+// queryControl sends command to the running instance and returns its reply.
+func queryControl(command string) (string, error) {
+	path := controlSocketPath()
+	conn, err := net.DialTimeout("unix", path, ctlTimeout)
+	if err != nil {
+		return "", fmt.Errorf("no running gopomotime found (%s)", path)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * ctlTimeout))
+
+	fmt.Fprintln(conn, command)
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("no reply from gopomotime: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "error: ") {
+		return "", errors.New(strings.TrimPrefix(reply, "error: "))
+	}
+	return reply, nil
+}
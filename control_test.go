@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitCommandOnSummaryScreen(t *testing.T) {
+	m := newTestModel(25*time.Minute, 10*time.Minute)
+	m.showingSummary = true
+	reply := make(chan string, 1)
+
+	_, cmd := m.Update(controlMsg{command: quitCommand, reply: reply})
+	if cmd == nil {
+		t.Fatal("quit on the summary screen returned no command, want tea.Quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("quit on the summary screen did not quit")
+	}
+	if got := <-reply; got == "" {
+		t.Error("quit on the summary screen sent no reply")
+	}
+}
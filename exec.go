@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// execDoneMsg reports how an --exec command finished.
+type execDoneMsg struct {
+	err error
+}
+
+// execCmd runs command with sh when a timer finishes (--exec), off the UI
+// goroutine. The timer's label and planned length (mm:ss) are passed in
+// GOPOMO_LABEL and GOPOMO_DURATION; the command's output is discarded.
+func execCmd(command, label string, duration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(), "GOPOMO_LABEL="+label, "GOPOMO_DURATION="+formatClock(duration))
+		return execDoneMsg{err: cmd.Run()}
+	}
+}
+
+// syslogCmd records a finished timer in the system log (--syslog), off the UI
+// goroutine, e.g. `timer finished: 25:00 "Write report"`.
+func syslogCmd(l *systemLog, label string, duration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		msg := "timer finished: " + formatClock(duration)
+		if label != "" {
+			msg += fmt.Sprintf(" %q", label)
+		}
+		l.info(msg)
+		return nil
+	}
+}
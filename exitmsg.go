@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultExitMessage is printed when the program closes, unless --no-exit-message is set.
+const defaultExitMessage = "Focused for {elapsed} — nice work!"
+
+// exitFields are the placeholders an --exit-message template may use.
+var exitFields = map[string]bool{"{elapsed}": true, "{label}": true, "{completed}": true, "{score}": true}
+
+// validateExitMessage reports the first unknown placeholder in format.
+func validateExitMessage(format string) error {
+	for _, p := range titlePlaceholder.FindAllString(format, -1) {
+		if !exitFields[p] {
+			return fmt.Errorf("unknown --exit-message placeholder %s (use {elapsed}, {label}, {completed} or {score})", p)
+		}
+	}
+	return nil
+}
+
+// exitMessage renders format from the final model, or returns "" when no time
+// was spent on the timer (e.g. quit while still waiting for --at).
+func exitMessage(format string, m model) string {
+	elapsed := m.elapsedTime
+	if m.isRunning && !m.isPaused && m.waitUntil.IsZero() && !m.showingSummary {
+		elapsed = time.Since(m.startTime) // Quit between ticks, or with Ctrl-C
+	}
+	if !m.inOvertime {
+		elapsed = min(elapsed, m.totalTime)
+	}
+	if elapsed < time.Second {
+		return ""
+	}
+	completed := "no"
+	if elapsed >= m.totalTime {
+		completed = "yes"
+	}
+	score := "" // Only rated with --score
+	if m.opts.score {
+		score = strconv.Itoa(m.focusScore())
+	}
+	return strings.TrimSpace(strings.NewReplacer(
+		"{elapsed}", formatClock(elapsed),
+		"{label}", m.label,
+		"{completed}", completed,
+		"{score}", score,
+	).Replace(format))
+}
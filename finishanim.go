@@ -0,0 +1,27 @@
+package main
+
+// spinSteps is how many blinks the --finished-anim spin marker takes to go
+// once around the ring.
+const spinSteps = 12
+
+// spinWidth is how far either side of the spin marker, as a fraction of the ring, is lit.
+const spinWidth = 1.0 / 30
+
+// sparkleShare is roughly one in how many ring cells light up on each sparkle frame.
+const sparkleShare = 7
+
+// animLit reports whether the ring at pos (0 to 1 clockwise from the start
+// angle) is lit in the current --finished-anim frame.
+func (r ringOptions) animLit(pos float64) bool {
+	switch r.finishedAnim {
+	case "spin":
+		return ringDistance(pos, float64(r.animFrame%spinSteps)/spinSteps) < spinWidth
+	case "pulse":
+		return r.animFrame%2 == 0
+	case "sparkle":
+		// A cheap hash of position and frame, so each frame lights a different scatter
+		h := uint32(pos*997)*2654435761 ^ uint32(r.animFrame)*40503
+		return (h>>16)%sparkleShare == 0
+	}
+	return false
+}
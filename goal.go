@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// goalRings and goalRingSpacing make the --daily-goal fanfare: a quick run of
+// bells, after the usual completion sound, unlike any other alert.
+const (
+	goalRings       = 5
+	goalRingSpacing = 120 * time.Millisecond
+	goalDelay       = time.Second
+)
+
+// completedOn counts the completed sessions in entries that ended on the same
+// local day as now.
+func completedOn(entries []historyEntry, now time.Time) int {
+	today := now.Format(time.DateOnly)
+	n := 0
+	for _, e := range entries {
+		if e.Completed && e.End.In(now.Location()).Format(time.DateOnly) == today {
+			n++
+		}
+	}
+	return n
+}
+
+// checkDailyGoal celebrates when the session just logged is the one that
+// reaches --daily-goal for today: it marks the goal reached, for the sparkle
+// on the finished screen, and returns the fanfare. While a sequence carries
+// on, the goal is announced in the status line instead.
+func (m *model) checkDailyGoal() tea.Cmd {
+	if m.opts.dailyGoal == 0 || m.goalReached {
+		return nil
+	}
+	entries, err := readHistory(m.opts.historyPath)
+	if err != nil || completedOn(entries, time.Now()) != m.opts.dailyGoal {
+		return nil
+	}
+	m.goalReached = true
+	var cmds []tea.Cmd
+	if m.isRunning {
+		m.flashText = truncateText(m.goalText(), width)
+		m.flashUntil = time.Now().Add(phaseMessageDuration)
+		cmds = append(cmds, tea.Tick(phaseMessageDuration, func(t time.Time) tea.Msg { return flashMsg{} }))
+	}
+	if m.soundAllowed() {
+		for i := range goalRings {
+			cmds = append(cmds, tea.Tick(goalDelay+time.Duration(i)*goalRingSpacing, func(t time.Time) tea.Msg { return bellMsg{} }))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// goalText announces the reached --daily-goal.
+func (m model) goalText() string {
+	return fmt.Sprintf("🎯 Daily goal: %d sessions!", m.opts.dailyGoal)
+}
+
+// goalLines returns the --daily-goal line for the finished screen, once reached.
+func (m model) goalLines() []string {
+	if !m.goalReached {
+		return nil
+	}
+	return []string{m.goalText()}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// finishWithGoal returns a model whose only timer has just run out, with a
+// history one completed session short of a --daily-goal of 2.
+func finishWithGoal(t *testing.T, onComplete string) model {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Now()
+	if err := appendHistory(path, historyEntry{Start: now.Add(-time.Minute), End: now, PlannedSeconds: 60, ElapsedSeconds: 60, Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(time.Minute, time.Minute)
+	m.opts.historyPath = path
+	m.opts.dailyGoal = 2
+	m.opts.onComplete = onComplete
+	m.plan = []segment{{duration: time.Minute}}
+	m.startTime = now.Add(-time.Minute)
+	m.sessionStart = m.startTime
+	return m
+}
+
+func TestDailyGoalOnQuit(t *testing.T) {
+	m := finishWithGoal(t, "quit")
+	m.complete()
+	if !m.goalReached {
+		t.Error("goal not reached by the session that meets it under --on-complete quit")
+	}
+}
+
+func TestDailyGoalResetsOnLoop(t *testing.T) {
+	m := finishWithGoal(t, "loop")
+	m.goalReached = true // Reached on an earlier day of a long-lived process
+	m.complete()
+	if m.flashText != m.goalText() {
+		t.Errorf("flashText = %q, want today's goal announced again after an earlier day's", m.flashText)
+	}
+}
+
+func TestWaitForNextRunClearsGoal(t *testing.T) {
+	m := newTestModel(time.Minute, time.Minute)
+	m.plan = []segment{{duration: time.Minute}}
+	m.opts.scheduleDaily = "09:00"
+	m.goalReached = true
+	m.waitForNextRun()
+	if m.goalReached {
+		t.Error("goalReached carried over to the next day's run")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gridGap is the number of blank columns between donuts in --grid mode.
+const gridGap = 2
+
+// gridTimer is one independent countdown in --grid mode.
+type gridTimer struct {
+	label       string
+	totalTime   time.Duration
+	elapsedTime time.Duration
+	startTime   time.Time
+	isPaused    bool
+}
+
+// finished reports whether the timer has run out.
+func (t gridTimer) finished() bool {
+	return t.elapsedTime >= t.totalTime
+}
+
+// gridModel runs several timers side by side, with keys acting on the focused one.
+type gridModel struct {
+	timers    []gridTimer
+	focus     int
+	blink     bool
+	termWidth int
+
+	muted   bool // No bell when a timer finishes (--mute)
+	ticking bool // A tickMsg is scheduled; the loop stops once every timer has finished
+}
+
+// parseGridArgs parses "mm:ss" or "mm:ss=label" arguments into grid timers.
+func parseGridArgs(args []string, now time.Time) ([]gridTimer, error) {
+	timers := make([]gridTimer, 0, len(args))
+	for i, arg := range args {
+		spec, label, _ := strings.Cut(arg, "=")
+		duration, err := parseDuration(spec)
+		if err != nil {
+			return nil, fmt.Errorf("timer %d: %v", i+1, err)
+		}
+		if label == "" {
+			label = fmt.Sprintf("Timer %d", i+1)
+		}
+		timers = append(timers, gridTimer{label: label, totalTime: duration, startTime: now})
+	}
+	return timers, nil
+}
+
+// Init starts the shared tick and blink loops. The model is created with
+// ticking set to match.
+func (g gridModel) Init() tea.Cmd {
+	return tea.Batch(tickCmd(), blinkCmd(blinkRate))
+}
+
+// Update advances all running timers on each tick and applies keys to the focused timer.
+func (g gridModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Paste {
+			return g, nil // Ignore pasted text
+		}
+		focused := &g.timers[g.focus]
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return g, tea.Quit
+		case "tab":
+			g.focus = (g.focus + 1) % len(g.timers)
+		case "shift+tab":
+			g.focus = (g.focus + len(g.timers) - 1) % len(g.timers)
+		case "p":
+			if !focused.finished() {
+				focused.isPaused = !focused.isPaused
+				// Keep elapsed time continuous across the pause
+				focused.startTime = time.Now().Add(-focused.elapsedTime)
+			}
+		case "r":
+			focused.elapsedTime = 0
+			focused.isPaused = false
+			focused.startTime = time.Now()
+			if !g.ticking {
+				g.ticking = true
+				return g, tickCmd() // Every timer had finished, so the loop had stopped
+			}
+		}
+	case tea.WindowSizeMsg:
+		g.termWidth = msg.Width
+	case tickMsg:
+		now := time.Now()
+		var bell tea.Cmd
+		running := false
+		for i := range g.timers {
+			t := &g.timers[i]
+			if !t.isPaused && !t.finished() {
+				t.elapsedTime = min(now.Sub(t.startTime), t.totalTime)
+				if t.finished() && !g.muted {
+					bell = bellCmd() // Once per tick, however many timers just finished
+				}
+			}
+			running = running || !t.finished()
+		}
+		if !running {
+			g.ticking = false // Nothing left to count; r starts the loop again
+			return g, bell
+		}
+		return g, tea.Batch(bell, tickCmd())
+	case blinkMsg:
+		g.blink = !g.blink
+		return g, blinkCmd(blinkRate)
+	}
+	return g, nil
+}
+
+// View lays the timers out in rows that fit the terminal width.
+func (g gridModel) View() string {
+	perRow := len(g.timers)
+	if g.termWidth > 0 {
+		perRow = max(1, (g.termWidth+gridGap)/(width+gridGap))
+	}
+
+	var rows []string
+	for start := 0; start < len(g.timers); start += perRow {
+		end := min(start+perRow, len(g.timers))
+		var cells []string
+		for i := start; i < end; i++ {
+			if i > start {
+				cells = append(cells, strings.Repeat(" ", gridGap))
+			}
+			cells = append(cells, g.renderTimer(i))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	controls := centerText("[tab] next [q]uit [r]eset [p]ause")
+	return lipgloss.JoinVertical(lipgloss.Left, append(rows, controls)...)
+}
+
+// renderTimer draws one timer: its label, donut, and status line.
+func (g gridModel) renderTimer(i int) string {
+	t := g.timers[i]
+
+	plain := truncateText(t.label, width-2)
+	label := plain
+	if i == g.focus {
+		label = markKey(label) // Mark the timer that keys act on
+	}
+	label = strings.Repeat(" ", max(0, (width-len([]rune(plain)))/2)) + label
+
+	remaining := t.totalTime - t.elapsedTime
+	timer := localizeDigits(formatClock(remaining))
+	progress := 0.0
+	if t.totalTime > 0 {
+		progress = float64(t.elapsedTime) / float64(t.totalTime)
+	}
+	circle := drawCircle(progress, timer, ringOptions{timerStyle: whiteStyle, paused: t.isPaused, secondHand: -1, celebrate: -1, breath: -1})
+
+	status := ""
+	switch {
+	case t.finished() && g.blink:
+		status = greenStyle.Render("Finished!")
+	case t.finished():
+		status = " "
+	case t.isPaused:
+		status = "Paused"
+	}
+	status = strings.Repeat(" ", max(0, (width-lipgloss.Width(status))/2)) + status
+
+	return lipgloss.NewStyle().Width(width).Render(label + "\n" + circle + "\n" + status)
+}
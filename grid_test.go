@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGridRingsAndStopsTicking(t *testing.T) {
+	now := time.Now()
+	g := gridModel{ticking: true, timers: []gridTimer{
+		{label: "Eggs", totalTime: time.Minute, startTime: now.Add(-time.Minute)},
+		{label: "Pasta", totalTime: time.Hour, startTime: now},
+	}}
+	ticks := func(cmd tea.Cmd) int { return countTicks(cmd, time.Second) }
+
+	// Catch the bells in a file instead of the terminal
+	out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(saved *os.File) { tuiOutput.File = saved }(tuiOutput.File)
+	tuiOutput.File = out
+	bells := func() int {
+		data, err := os.ReadFile(out.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(data), "\a")
+	}
+
+	// The first timer runs out: ring, and keep ticking for the second
+	next, cmd := g.Update(tickMsg(now))
+	g = next.(gridModel)
+	if !g.timers[0].finished() || g.timers[1].finished() {
+		t.Fatal("want only the first timer finished")
+	}
+	if ticks(cmd) != 1 || bells() != 1 {
+		t.Errorf("%d bells; want one bell and the next tick once a timer finishes", bells())
+	}
+
+	// Muted, the last timer runs out silently, and the loop stops
+	g.muted = true
+	g.timers[1].startTime = now.Add(-time.Hour)
+	next, cmd = g.Update(tickMsg(now))
+	g = next.(gridModel)
+	if ticks(cmd) != 0 || bells() != 1 {
+		t.Error("want no bell and no tick once every timer has finished")
+	}
+	if g.ticking {
+		t.Error("ticking = true with no tick scheduled")
+	}
+
+	// Resetting a timer starts the loop again
+	next, cmd = g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	g = next.(gridModel)
+	if !g.ticking || ticks(cmd) != 1 {
+		t.Error("want r to restart the tick loop")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// importTimeLayouts are the start times accepted by `history import`, tried
+// in order; the ones without a zone are read as local time.
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// parseImportRow turns a "start,duration[,label]" CSV row into a completed
+// history entry. The duration is mm:ss or a Go duration such as 25m or 1h30m.
+func parseImportRow(row []string) (historyEntry, error) {
+	if len(row) < 2 || len(row) > 3 {
+		return historyEntry{}, fmt.Errorf("expected start,duration[,label], got %d fields", len(row))
+	}
+	var start time.Time
+	var err error
+	for _, layout := range importTimeLayouts {
+		if start, err = time.ParseInLocation(layout, strings.TrimSpace(row[0]), time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return historyEntry{}, fmt.Errorf("invalid start %q, expected e.g. 2024-05-01 09:30 or RFC 3339", row[0])
+	}
+	field := strings.TrimSpace(row[1])
+	duration, err := time.ParseDuration(field)
+	if err != nil {
+		if duration, err = parseDuration(field); err != nil {
+			return historyEntry{}, fmt.Errorf("invalid duration %q, expected mm:ss or e.g. 25m", row[1])
+		}
+	}
+	if duration <= 0 {
+		return historyEntry{}, fmt.Errorf("duration %q must be positive", row[1])
+	}
+	entry := historyEntry{
+		Start:          start,
+		End:            start.Add(duration),
+		PlannedSeconds: int(duration.Seconds()),
+		ElapsedSeconds: int(duration.Seconds()),
+		Completed:      true,
+		WallSeconds:    int(duration.Seconds()),
+	}
+	if len(row) == 3 {
+		entry.Label = strings.TrimSpace(row[2])
+	}
+	return entry, nil
+}
+
+// importHistory appends the sessions in the CSV file at csvPath to the history
+// at historyPath. A first row starting with "start" is taken as a header. Every
+// row is checked first, so a file with bad rows adds nothing; the returned
+// error then lists each bad row by line.
+func importHistory(csvPath, historyPath string) (int, error) {
+	f, err := os.Open(csvPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1 // Checked per row, for a clearer message
+	r.TrimLeadingSpace = true
+	var entries []historyEntry
+	var bad []string
+	for first := true; ; first = false {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := r.FieldPos(0)
+		if err != nil {
+			return 0, fmt.Errorf("%s:%d: %v", csvPath, line, err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(row[0]), "start") {
+			continue
+		}
+		entry, err := parseImportRow(row)
+		if err != nil {
+			bad = append(bad, fmt.Sprintf("%s:%d: %v", csvPath, line, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(bad) > 0 {
+		return 0, errors.New(strings.Join(bad, "\n"))
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("%s: no sessions found", csvPath)
+	}
+	for i, entry := range entries {
+		if err := appendHistory(historyPath, entry); err != nil {
+			return i, err
+		}
+	}
+	return len(entries), nil
+}
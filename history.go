@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyEntry is one line of the JSONL session history.
+type historyEntry struct {
+	Label           string    `json:"label,omitempty"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	PlannedSeconds  int       `json:"planned_seconds"`
+	ElapsedSeconds  int       `json:"elapsed_seconds"`
+	Completed       bool      `json:"completed"`
+	OvertimeSeconds int       `json:"overtime_seconds,omitempty"`
+	PausedSeconds   int       `json:"paused_seconds"`
+	WallSeconds     int       `json:"wall_seconds"`
+	Score           *int      `json:"score,omitempty"` // Focus score, with --score
+	Category        string    `json:"category,omitempty"`
+}
+
+// appendHistory appends a single entry to the history file at path, creating it if needed.
+func appendHistory(path string, entry historyEntry) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// logSession writes the current session to the history file, and completed
+// sessions to the org log, at most once per session.
+// Logging is best-effort: the TUI has nowhere to report a failed write.
+func (m *model) logSession() {
+	if (m.opts.historyPath == "" && m.opts.orgLogPath == "") || m.logged || !m.waitUntil.IsZero() {
+		return
+	}
+	m.logged = true
+
+	// Skip sessions abandoned before --min-log; completed sessions always count
+	completed := m.elapsedTime >= m.totalTime
+	if !completed && m.elapsedTime < m.opts.minLog {
+		return
+	}
+
+	now := time.Now()
+	paused := m.pausedTime
+	if m.isPaused && len(m.commandQueue) == 0 {
+		paused += now.Sub(m.pausedAt) // Quit or stopped while paused, but not while plan commands held the timer
+	}
+	entry := historyEntry{
+		Label:          m.label,
+		Start:          m.sessionStart,
+		End:            now,
+		PlannedSeconds: int(m.totalTime.Seconds()),
+		ElapsedSeconds: m.logSeconds(m.elapsedTime - m.loggedBlocks),
+		Completed:      completed,
+		PausedSeconds:  m.logSeconds(paused),
+		WallSeconds:    m.logSeconds(now.Sub(m.sessionStart)),
+		Category:       m.category,
+	}
+	if m.opts.stopwatch {
+		// A stopwatch has no plan and ends whenever it is stopped
+		entry.PlannedSeconds = 0
+		entry.Completed = true
+	}
+	if m.opts.score {
+		score := m.focusScore()
+		entry.Score = &score
+	}
+	if m.elapsedTime > m.totalTime {
+		entry.OvertimeSeconds = m.logSeconds(m.elapsedTime - m.totalTime)
+	}
+	if m.opts.historyPath != "" {
+		_ = appendHistory(m.opts.historyPath, entry)
+	}
+	if m.opts.orgLogPath != "" && entry.Completed {
+		_ = appendOrgClock(m.opts.orgLogPath, m.label, entry.Start, entry.End)
+	}
+}
+
+// logSeconds converts a measured duration to whole seconds for the history,
+// rounded to the --round-log increment when one is set.
+func (m model) logSeconds(d time.Duration) int {
+	if m.opts.roundLog > 0 {
+		d = d.Round(m.opts.roundLog)
+	}
+	return int(d.Seconds())
+}
+
+// lastTimer returns the most recent timed (non-stopwatch) entry in the history file at path.
+func lastTimer(path string) (historyEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return historyEntry{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		var entry historyEntry
+		if json.Unmarshal([]byte(lines[i]), &entry) == nil && entry.PlannedSeconds > 0 {
+			return entry, nil
+		}
+	}
+	return historyEntry{}, fmt.Errorf("%s: no timers in history", path)
+}
+
+// endPause is called when resuming at resumeAt. It adds the pause to the
+// session's paused time, unless the session was split around it.
+func (m *model) endPause(resumeAt time.Time) {
+	if m.splitSession(resumeAt) {
+		m.pausedTime = 0 // The pause falls between the two entries
+		return
+	}
+	m.pausedTime += resumeAt.Sub(m.pausedAt)
+}
+
+// splitSession is called when resuming at resumeAt. After a pause longer than
+// --split-pause it logs the active block before the pause as its own
+// unfinished entry, so the rest of the session is logged from resumeAt with
+// only the time worked since. It reports whether the session was split.
+func (m *model) splitSession(resumeAt time.Time) bool {
+	if m.opts.splitPause <= 0 || m.opts.historyPath == "" || m.opts.stopwatch || resumeAt.Sub(m.pausedAt) <= m.opts.splitPause {
+		return false
+	}
+	entry := historyEntry{
+		Label:          m.label,
+		Start:          m.sessionStart,
+		End:            m.pausedAt,
+		PlannedSeconds: int(m.totalTime.Seconds()),
+		ElapsedSeconds: m.logSeconds(m.elapsedTime - m.loggedBlocks),
+		PausedSeconds:  m.logSeconds(m.pausedTime),
+		WallSeconds:    m.logSeconds(m.pausedAt.Sub(m.sessionStart)),
+		Category:       m.category,
+	}
+	if m.opts.score {
+		score := m.focusScore() // Scored as unfinished, like any block stopped early
+		entry.Score = &score
+	}
+	_ = appendHistory(m.opts.historyPath, entry)
+	m.loggedBlocks = m.elapsedTime
+	m.sessionStart = resumeAt
+	return true
+}
+
+// runHistory implements `gopomotime history clear|rotate`, acting on the
+// --history file given after the command or set in config.toml. It returns the exit code.
+func runHistory(args []string) int {
+	fset := flag.NewFlagSet("history", flag.ContinueOnError)
+	path := fset.String("history", configHistoryPath(), "history `file` to act on")
+	yes := fset.Bool("yes", false, "clear without asking for confirmation")
+	fset.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gopomotime history clear|rotate [--history file] [--yes]")
+		fmt.Fprintln(os.Stderr, "       gopomotime history import file.csv [--history file]")
+		fset.PrintDefaults()
+	}
+	if len(args) == 0 || (args[0] != "clear" && args[0] != "rotate" && args[0] != "import") {
+		fset.Usage()
+		return 2
+	}
+	rest, csvPath := args[1:], ""
+	if args[0] == "import" && len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		csvPath, rest = rest[0], rest[1:] // The file may come before the flags
+	}
+	if err := fset.Parse(rest); err != nil {
+		return 2
+	}
+	if args[0] == "import" && csvPath == "" {
+		csvPath = fset.Arg(0)
+	}
+	if args[0] == "import" && (csvPath == "" || fset.NArg() > 1) {
+		fset.Usage()
+		return 2
+	}
+	if *path == "" {
+		fmt.Fprintln(os.Stderr, "Error: no history file; pass --history or set history in config.toml")
+		return 1
+	}
+	if args[0] == "import" {
+		// Appending creates the history file if there is none yet
+		n, err := importHistory(csvPath, *path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Printf("Imported %d sessions into %s\n", n, *path)
+		return 0
+	}
+	if _, err := os.Stat(*path); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "clear":
+		if !*yes {
+			fmt.Printf("Delete all sessions in %s? [y/N] ", *path)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("Cancelled.")
+				return 1
+			}
+		}
+		if err := os.Truncate(*path, 0); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Println("Cleared", *path)
+	case "rotate":
+		rotated := rotatedName(*path, time.Now())
+		if err := os.Rename(*path, rotated); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		if err := os.WriteFile(*path, nil, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Println("Moved old sessions to", rotated)
+	}
+	return 0
+}
+
+// rotatedName returns an unused name for path with the date before the extension,
+// e.g. sessions-2024-05-01.jsonl, adding a counter if rotated more than once a day.
+func rotatedName(path string, now time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "-" + now.Format("2006-01-02")
+	name := base + ext
+	for i := 2; ; i++ {
+		if _, err := os.Stat(name); errors.Is(err, fs.ErrNotExist) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
+// configHistoryPath returns the history file set in config.toml, or "" if none.
+func configHistoryPath() string {
+	path, source := configPath()
+	if path == "" {
+		return ""
+	}
+	entries, err := parseConfig(path, source == "$"+configEnv)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if e.key == "history" {
+			return e.value
+		}
+	}
+	return ""
+}
+
+// readHistory returns every entry in the history file at path, skipping lines that do not parse.
+func readHistory(path string) ([]historyEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		var entry historyEntry
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// focusStreak counts the consecutive days, ending today, with at least one
+// completed session. A streak last extended yesterday still counts, since today
+// is not over yet.
+func focusStreak(entries []historyEntry, now time.Time) int {
+	days := map[string]bool{}
+	for _, e := range entries {
+		if e.Completed {
+			days[e.End.In(now.Location()).Format(time.DateOnly)] = true
+		}
+	}
+	day := now
+	if !days[day.Format(time.DateOnly)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for days[day.Format(time.DateOnly)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
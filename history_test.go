@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitSessionKeepsCategoryAndScore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	m := newTestModel(25*time.Minute, 10*time.Minute)
+	m.opts.historyPath = path
+	m.opts.splitPause = 30 * time.Minute
+	m.opts.score = true
+	m.opts.pausePenalty = 10
+	m.opts.incompletePenalty = 30
+	m.category = "writing"
+	m.isPaused = true
+	m.pauseCount = 1
+	m.pausedAt = testEpoch.Add(10 * time.Minute)
+
+	m.endPause(m.pausedAt.Add(time.Hour))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry historyEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Category != "writing" {
+		t.Errorf("category = %q, want the session's", entry.Category)
+	}
+	if entry.Score == nil || *entry.Score != 60 {
+		t.Errorf("score = %v, want 60 for one pause and an unfinished block", entry.Score)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// messages holds the on-screen strings for one locale. Control labels must
+// keep their key in brackets (e.g. "[q]") so the keybindings stay the same.
+type messages struct {
+	Finished string `json:"finished"`
+	Paused   string `json:"paused"`
+	Stopped  string `json:"stopped"`
+	Quit     string `json:"quit"`
+	Reset    string `json:"reset"`
+	Pause    string `json:"pause"`
+	Unpause  string `json:"unpause"`
+
+	PausedAway    string `json:"paused_away"`    // Paused by --idle-pause
+	PressToStart  string `json:"press_to_start"` // Before --clock-then-donut is started
+	Inhale        string `json:"inhale"`         // --breathe cue while breathing in
+	Exhale        string `json:"exhale"`         // --breathe cue while breathing out
+	Running       string `json:"running"`        // Before a plan command that holds the timer
+	Failed        string `json:"failed"`         // Before a plan command that failed
+	CommandPrompt string `json:"command_prompt"` // The keys offered after a failure
+	FinishedIdle  string `json:"finished_idle"`  // Finished screen left alone until it stopped blinking
+	RestartHint   string `json:"restart_hint"`   // Below FinishedIdle
+	WaitingUntil  string `json:"waiting_until"`  // Before the --at start time
+	PauseStops    string `json:"pause_stops"`    // --pause-timeout countdown to stopping
+	PauseResumes  string `json:"pause_resumes"`  // --pause-timeout countdown to resuming
+	AllDone       string `json:"all_done"`       // In place of Finished with --on-complete celebrate
+	Locked        string `json:"locked"`         // Flashed when --strict holds q and r back
+	PausedTag     string `json:"paused_tag"`     // After the time in the compact view
+	FinishedTag   string `json:"finished_tag"`   // After the time in the compact view
+	TooSmall      string `json:"too_small"`      // Below the compact view
+
+	// The --summary screen; the row names are padded to line up
+	SummaryTitle   string `json:"summary_title"`
+	SummaryLabel   string `json:"summary_label"`
+	SummaryPlanned string `json:"summary_planned"`
+	SummaryActual  string `json:"summary_actual"`
+	SummaryStatus  string `json:"summary_status"`
+	SummaryPauses  string `json:"summary_pauses"`
+	SummaryScore   string `json:"summary_score"`
+	Completed      string `json:"completed"`
+	StoppedEarly   string `json:"stopped_early"`
+	AnyKeyToExit   string `json:"any_key_to_exit"`
+	QToExit        string `json:"q_to_exit"`
+
+	sep string // Between control labels; a single space when empty
+}
+
+// msgs is the catalog in use, selected once at startup by loadMessages.
+var msgs = messages{
+	Finished: "Timer finished!",
+	Paused:   "Timer paused.",
+	Stopped:  "Timer stopped.",
+	Quit:     "[q]uit",
+	Reset:    "[r]eset",
+	Pause:    "[p]ause",
+	Unpause:  "un[p]ause",
+
+	PausedAway:    "Paused while away",
+	PressToStart:  "Press p to start",
+	Inhale:        "Inhale",
+	Exhale:        "Exhale",
+	Running:       "Running:",
+	Failed:        "Failed:",
+	CommandPrompt: "p continues, q aborts",
+	FinishedIdle:  "Finished (idle)",
+	RestartHint:   "press r to restart",
+	WaitingUntil:  "Waiting until",
+	PauseStops:    "Paused, stops in",
+	PauseResumes:  "Paused, resumes in",
+	AllDone:       "All done!",
+	Locked:        "locked until done",
+	PausedTag:     "(paused)",
+	FinishedTag:   "(finished)",
+	TooSmall:      "(terminal too small for donut)",
+
+	SummaryTitle:   "Session summary",
+	SummaryLabel:   "Label:",
+	SummaryPlanned: "Planned:",
+	SummaryActual:  "Actual:",
+	SummaryStatus:  "Status:",
+	SummaryPauses:  "Pauses:",
+	SummaryScore:   "Score:",
+	Completed:      "Completed",
+	StoppedEarly:   "Stopped early",
+	AnyKeyToExit:   "Press any key to exit",
+	QToExit:        "Press q to exit",
+}
+
+// controls returns the control line, offering un[p]ause when paused.
+func (c messages) controls(paused bool) string {
+	pause := c.Pause
+	if paused {
+		pause = c.Unpause
+	}
+	sep := c.sep
+	if sep == "" {
+		sep = " "
+	}
+	return c.Quit + sep + c.Reset + sep + pause
+}
+
+// localeFromEnv returns the language code from LC_ALL, LC_MESSAGES or LANG
+// (e.g. "de" for "de_DE.UTF-8"), or "" if none is set.
+func localeFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			lang, _, _ := strings.Cut(v, "_")
+			lang, _, _ = strings.Cut(lang, ".")
+			return strings.ToLower(lang)
+		}
+	}
+	return ""
+}
+
+// loadMessages returns the catalog for lang, falling back to English when
+// the locale is not supported.
+func loadMessages(lang string) (messages, error) {
+	data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+	if err != nil {
+		data, err = localeFiles.ReadFile("locales/en.json")
+		if err != nil {
+			return messages{}, err
+		}
+	}
+	var c messages
+	if err := json.Unmarshal(data, &c); err != nil {
+		return messages{}, fmt.Errorf("locale %s: %v", lang, err)
+	}
+	for key, label := range map[string]string{"[q]": c.Quit, "[r]": c.Reset, "[p]": c.Pause} {
+		if !strings.Contains(label, key) {
+			return messages{}, fmt.Errorf("locale %s: control %q must contain %s", lang, label, key)
+		}
+	}
+	if !strings.Contains(c.Unpause, "[p]") {
+		return messages{}, fmt.Errorf("locale %s: control %q must contain [p]", lang, c.Unpause)
+	}
+	return c, nil
+}
+
+// withIcons returns c with the control labels drawn as media icons plus their
+// key (--icons). The keys themselves are unchanged.
+func (c messages) withIcons() messages {
+	c.Quit = "⏹ q"
+	c.Reset = "↺ r"
+	c.Pause = "⏸ p"
+	c.Unpause = "▶ p"
+	c.sep = "   " // Keep each icon visibly paired with its key
+	return c
+}
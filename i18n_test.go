@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestLocalesComplete checks that every embedded catalog loads and sets every
+// string the English one does, so no status line is left blank in another language.
+func TestLocalesComplete(t *testing.T) {
+	var keys map[string]string
+	english, err := json.Marshal(msgs)
+	if err == nil {
+		err = json.Unmarshal(english, &keys)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := fs.Glob(localeFiles, "locales/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no locale files embedded (%v)", err)
+	}
+	for _, file := range files {
+		lang := strings.TrimSuffix(strings.TrimPrefix(file, "locales/"), ".json")
+		if _, err := loadMessages(lang); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+		data, err := localeFiles.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var strs map[string]string
+		if err := json.Unmarshal(data, &strs); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		for key := range keys {
+			if strs[key] == "" {
+				t.Errorf("%s: no %q string", file, key)
+			}
+		}
+	}
+}
+
+// TestSummaryLinesAlign checks that the summary values line up however long
+// the translated row names are.
+func TestSummaryLinesAlign(t *testing.T) {
+	m := newTestModel(25*time.Minute, 25*time.Minute)
+	m.isRunning = false
+	m.label = "Write"
+	m.pauseCount = 2
+	want := []string{
+		"Label:   Write",
+		"Planned: 25:00",
+		"Actual:  25:00",
+		"Status:  Completed",
+		"Pauses:  2",
+	}
+	if got := m.summaryLines(); !slices.Equal(got, want) {
+		t.Errorf("English summary\n%q\nwant\n%q", got, want)
+	}
+
+	defer func(saved messages) { msgs = saved }(msgs)
+	var err error
+	if msgs, err = loadMessages("de"); err != nil {
+		t.Fatal(err)
+	}
+	column := -1
+	for _, line := range m.summaryLines() {
+		name, _, _ := strings.Cut(line, ":")
+		value := strings.TrimLeft(line[len(name)+1:], " ")
+		at := lipgloss.Width(line) - lipgloss.Width(value)
+		if column >= 0 && at != column {
+			t.Errorf("%q: value at column %d, want %d", line, at, column)
+		}
+		column = at
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckInterval is how often --idle-pause asks for the desktop idle time.
+const idleCheckInterval = time.Second
+
+// idleMsg carries the desktop idle time for --idle-pause.
+type idleMsg struct {
+	idle time.Duration
+	err  error
+}
+
+// idleAvailable reports whether the desktop idle time can be read: an X11
+// display and xprintidle on the PATH.
+func idleAvailable() bool {
+	if os.Getenv("DISPLAY") == "" {
+		return false
+	}
+	_, err := exec.LookPath("xprintidle")
+	return err == nil
+}
+
+// idleCheckCmd reads the time since the last keyboard or mouse input with
+// xprintidle, after idleCheckInterval.
+func idleCheckCmd() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(t time.Time) tea.Msg {
+		out, err := exec.Command("xprintidle").Output()
+		if err != nil {
+			return idleMsg{err: err}
+		}
+		ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		return idleMsg{idle: time.Duration(ms) * time.Millisecond, err: err}
+	})
+}
+
+// handleIdle pauses a running timer once the desktop has been idle for
+// --idle-pause, backdated to the last input, and resumes it on the next input
+// if it was paused that way. A failing xprintidle stops the checks.
+func (m model) handleIdle(msg idleMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m.flashError("idle-pause: xprintidle failed, checks stopped")
+	}
+	now := time.Now()
+	switch {
+	case m.isRunning && !m.isPaused && !m.pendingPauseToggle && m.waitUntil.IsZero() && msg.idle >= m.opts.idlePause:
+		// Stepped away: stop counting from the last input
+		m.isPaused = true
+		m.autoPaused = true
+		m.pausedAt = now.Add(-msg.idle)
+		if m.pausedAt.Before(m.startTime) {
+			m.pausedAt = m.startTime // Idle since before this timer began
+		}
+		m.elapsedTime = m.pausedAt.Sub(m.startTime)
+		m.pauseCount++
+		if m.opts.pauseTimeout > 0 {
+			return m, tea.Batch(idleCheckCmd(), pauseCheckCmd(m.pausedAt))
+		}
+	case m.autoPaused && m.isPaused && msg.idle < idleCheckInterval:
+		// Back at the keyboard
+		m.endPause(now)
+		m.isPaused = false
+		m.autoPaused = false
+		m.startTime = now.Add(-m.elapsedTime)
+		return m, tea.Batch(idleCheckCmd(), m.startTick(), m.completeCmd())
+	}
+	return m, idleCheckCmd()
+}
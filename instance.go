@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// replaceTimeout bounds how long --on-existing replace waits for the running
+// instance to exit.
+const replaceTimeout = 5 * time.Second
+
+// lockPath returns the single-instance pidfile, next to the control socket.
+func lockPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gopomotime.pid")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gopomotime-%d.pid", os.Getuid()))
+}
+
+// errRunning is returned by acquireLock when another instance holds the lock.
+type errRunning struct {
+	pid int
+}
+
+func (e errRunning) Error() string {
+	return fmt.Sprintf("another gopomotime is already running (pid %d)", e.pid)
+}
+
+// acquireLock creates the pidfile at path holding this process's pid, and
+// returns a func that removes it. A pidfile left behind by a process that has
+// exited is replaced; one owned by a live process is an errRunning.
+func acquireLock(path string) (release func(), err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintln(f, os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if pid, ok := lockOwner(path); ok && processAlive(pid) {
+			return nil, errRunning{pid}
+		}
+		os.Remove(path) // Stale pidfile from a crash
+	}
+	return nil, fmt.Errorf("could not take the lock %s", path)
+}
+
+// lockOwner reads the pid in the pidfile at path.
+func lockOwner(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid, err == nil && pid > 0
+}
+
+// processAlive reports whether a process with the given pid is running.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// replaceRunning asks the instance holding the lock to quit, through the
+// control socket so it logs its session, or with SIGTERM when it has no
+// socket. It then takes the lock once the instance has exited.
+func replaceRunning(path string, pid int) (release func(), err error) {
+	if _, err := queryControl(quitCommand); err != nil {
+		proc, err := os.FindProcess(pid)
+		if err == nil {
+			err = proc.Signal(syscall.SIGTERM)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not stop gopomotime (pid %d): %v", pid, err)
+		}
+	}
+	deadline := time.Now().Add(replaceTimeout)
+	for {
+		release, err := acquireLock(path)
+		if _, running := err.(errRunning); !running || time.Now().After(deadline) {
+			return release, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -1,19 +1,25 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
+	"math/rand"
 	"os"
+	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 const (
-	maxMinutes = 99
 	maxSeconds = 59
 	tickRate   = 120 * time.Millisecond // ~30 FPS for smooth progress
 	blinkRate  = 800 * time.Millisecond
@@ -21,25 +27,175 @@ const (
 	width      = 29 // Donut width
 )
 
+// maxMinutes is the largest minutes value accepted in mm:ss, set with --max-minutes.
+var maxMinutes = 99
+
+// maxMinutesLimit bounds --max-minutes, keeping the clock to three digits.
+const maxMinutesLimit = 999
+
+// options holds the settings chosen on the command line.
+type options struct {
+	overtime    bool          // Keep counting past zero instead of stopping
+	historyPath string        // JSONL session log, disabled when empty
+	orgLogPath  string        // Org-mode file for CLOCK entries of completed sessions, disabled when empty
+	minLog      time.Duration // Unfinished sessions shorter than this are not logged
+	roundLog    time.Duration // Round logged durations to this increment, disabled when zero
+
+	secondHand         bool          // Sweep a marker around the ring once per second
+	onComplete         string        // After the last timer: "wait", "quit", "celebrate" or "loop"
+	comet              bool          // Fade elapsed segments behind the leading edge
+	snapshotPath       string        // Write an SVG of the finished donut here on completion
+	summary            bool          // Show a review screen when quitting with q
+	stopwatch          bool          // Count up with no end
+	precise            bool          // Show hundredths in stopwatch mode
+	strict             bool          // Disable q and r while a timer runs (Ctrl-C still quits)
+	debug              bool          // Enable testing keys such as J (jump to the last seconds)
+	workEndSound       string        // Shell command run instead of the bell when a work phase ends
+	breakEndSound      string        // Shell command run instead of the bell when a break ends
+	workEndMessage     string        // Status message shown when a work phase ends
+	breakEndMessage    string        // Status message shown when a break ends
+	pauseTimeout       time.Duration // Act on a pause this long, disabled when zero
+	pauseTimeoutAction string        // "resume" or "stop"
+	splitPause         time.Duration // Log separate history entries across pauses this long, disabled when zero
+	link               string        // URL of the task, linked from the finished screen
+	finale             bool          // Pulse the ring in the final minute
+	finishedBlinkRate  time.Duration // Blink interval of the finished screen
+	finishedAnim       string        // Animate the full ring when finished: "spin", "pulse", "sparkle" or "none"
+	clockThenDonut     bool          // Start stopped, showing a big clock until p starts the donut
+	breathe            bool          // Pulse the ring and cue "Inhale"/"Exhale" on breathCycle
+	breathCycle        breathCycle   // Inhale and exhale times for --breathe
+	minuteMarks        bool          // Notch the ring at each whole minute
+	repeatSound        int           // Times to ring the completion bell
+	softFinish         bool          // Ring the bell in growing rounds instead of all at once
+	transitionSound    string        // Which mid-sequence phase ends sound: "all", "work-only", "break-only" or "none"
+	wrapLabel          bool          // Wrap long labels onto two lines instead of truncating them
+	persistSummary     bool          // Leave the alternate screen on finishing so the result stays in the scrollback
+	roundDisplay       bool          // Round the displayed seconds rather than truncating them
+	streak             bool          // Show the run of days with a completed session on the finished screen
+	dailyGoal          int           // Completed sessions a day that earn a celebration, disabled when zero
+	trendDays          int           // Days of focus time in the finished-screen sparkline, disabled when zero
+	categories         []string      // Category names picked with the keys 1-9
+	hideControls       bool          // Leave the control line out of the view (keys still work)
+	resetClearsLabel   bool          // Drop the label on r, so the restarted session is logged without it
+	scheduleDaily      string        // Local "hh:mm" to rerun the plan every day, disabled when empty
+	exec               string        // Shell command run whenever a timer finishes, disabled when empty
+	braille            bool          // Draw the ring with Braille dots
+	idlePause          time.Duration // Pause after this long without keyboard or mouse input, disabled when zero
+	score              bool          // Rate each session (focusScore) on the summary and in the history
+	pausePenalty       int           // Points off the score for each pause
+	incompletePenalty  int           // Points off the score for stopping early
+
+	speak   bool            // Announce milestones and the finish with text-to-speech
+	speakAt []time.Duration // Remaining times to announce, longest first
+}
+
+// Build information, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
 type model struct {
+	opts options
+
+	// Sequence of timers (a single entry unless --plan is used)
+	plan      []segment
+	planIndex int
+	label     string
+
 	totalTime   time.Duration
 	elapsedTime time.Duration
 	isRunning   bool
 	isPaused    bool
-	blink       bool // For blinking effect
+	inOvertime  bool          // Counting past totalTime (--overtime)
+	waitUntil   time.Time     // Scheduled start (--at), zero once running
+	pausedAt    time.Time     // When the current pause began
+	suspendedAt time.Time     // When the process was suspended with Ctrl+Z
+	pauseCount  int           // Pauses in the current session
+	pausedTime  time.Duration // Time spent in finished pauses of the current session
+	autoPaused  bool          // Paused by --idle-pause, so input resumes it
+	spoken      int           // --speak milestones already passed in the current timer
+	category    string        // Category of the current session, set with 1-9 (--categories)
+	goalReached bool          // This run completed today's --daily-goal session
+
+	// Plan file "!command" lines holding the current timer back (plancmd.go)
+	commandQueue  []string // Still to run; the first one is running or has failed
+	commandFailed string   // Why the first queued command failed; p continues, q aborts
+
+	showingSummary bool      // Quit pressed with --summary; showing the review screen
+	persisted      bool      // Finished with --persist-summary; drawn in the normal buffer until q
+	quitting       bool      // q pressed on the persisted screen; the last frame drops the hint
+	blink          bool      // For blinking effect
+	blinking       bool      // A blinkMsg is scheduled, so the loop is never started twice
+	ticking        bool      // A tickMsg is scheduled; startTick never runs two tick loops
+	blinkCount     int       // Blinks so far, drives the celebration animation
+	muted          bool      // Suppress the completion bell (toggled with m)
+	silenced       bool      // The last timer finished without its alert because sound was not allowed
+	showElapsed    bool      // Show elapsed instead of remaining time in the center (toggled with t)
+	revealUntil    time.Time // Show the time to the tenth of a second until then ('.')
+	streak         int       // Consecutive days with a completed session (--streak)
+	commanding     bool      // The ':' command line is open
+	commandLine    string    // Text typed after ':'
+	presets        []preset  // Presets from config.toml, cycled with P
+	pickingPreset  bool      // A preset has been picked with P and waits for Enter
+	presetIndex    int       // The picked preset
+	finishedAt     time.Time // Last finish or keypress on the finished screen, for idle detection
+	idle           bool      // Finished screen left alone too long; blinking stopped
 
 	// For key highlight feedback
 	highlightKey       string
 	highlightUntil     time.Time
 	pendingPauseToggle bool // If true, toggle pause on highlightMsg
 
+	// Terminal size from tea.WindowSizeMsg (zero until the first one arrives)
+	termWidth  int
+	termHeight int
+
+	// Short-lived message shown in the status area (e.g. "Copied")
+	flashText  string
+	flashUntil time.Time
+
 	// Add a new field to model to track the start time for smooth progress
 	startTime time.Time
+
+	// Time source for View, so a frame can be rendered at a fixed instant
+	// (e.g. to compare against a saved frame); nil means time.Now
+	clock func() time.Time
+
+	metrics  *metrics       // Shared with the --metrics server, nil when disabled
+	tmux     *tmuxStatus    // Mirrors the timer into tmux (--tmux), nil when disabled
+	title    *windowTitle   // Writes the timer into the terminal title (--title-format), nil when disabled
+	recorder *frameRecorder // Saves rendered frames (--record), nil when disabled
+	sysLog   *systemLog     // Records finished timers in the system log (--syslog), nil when disabled
+	timing   *tickTiming    // Measures tick delivery (--debug-timing), nil when disabled
+
+	// Finished-screen quote (--quotes)
+	quotes []string
+	quote  string
+	rng    *rand.Rand
+
+	// Finished-screen sparkline (--trend): focus time on each recent day, oldest first
+	trend []time.Duration
+
+	// For session history
+	sessionStart time.Time     // Wall clock start of the session, unaffected by pauses
+	logged       bool          // Session already written to history
+	loggedBlocks time.Duration // Active time already logged as earlier blocks (--split-pause)
 }
 
 type tickMsg time.Time
 type blinkMsg time.Time
 
+// completeMsg arrives at the exact moment a timer runs out, so completion does
+// not wait for the next tick. It carries the timing it was scheduled for, and
+// is ignored once a pause, reset or adjustment has made that stale.
+type completeMsg struct {
+	startTime time.Time
+	totalTime time.Duration
+}
+
 // Styling for the circle and text
 var (
 	redStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")) // Red for remaining time
@@ -47,12 +203,105 @@ var (
 	greenStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")) // Green for finished text
 	circleStyle    = lipgloss.NewStyle()                                       // No center alignment
 	highlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFa400")) // Orange highlight
+	overtimeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")) // Gold for overtime
+	handStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#00BFFF")) // Blue for the second hand
+	dimRedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B0000")) // Dimmed remaining time while paused
+	dimWhiteStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")) // Dimmed elapsed time while paused
+	finaleStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6060")) // Bright red for the --finale pulse
 )
 
+// Glyph drawn for ring cells (--char)
+var ringChar = "*"
+
+// Where the TUI and its escape sequences go (--tui-stream)
+var tuiOutput = &terminalOutput{File: os.Stdout}
+
+// terminalOutput is the terminal the TUI draws on. Writes are serialized, so
+// a bell rung from a command never lands in the middle of a frame. It stays
+// an *os.File underneath, so Bubble Tea still finds the terminal's size.
+type terminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (o *terminalOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(p)
+}
+
+func (o *terminalOutput) WriteString(s string) (int, error) {
+	return o.Write([]byte(s))
+}
+
+// Where the fill begins, in radians clockwise from 12 o'clock (--start-angle)
+var startAngle float64
+
+// Colors cycled around the ring by --on-complete celebrate
+var celebrateStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#FF7F00")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#0000FF")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#8B00FF")),
+}
+
 // Highlight duration for key feedback
 const highlightDuration = 150 * time.Millisecond
 
+// How long '.' shows the time to the tenth of a second
+const revealDuration = 2 * time.Second
+
+// Flash duration for transient status messages
+const flashDuration = 1 * time.Second
+
+// Gap between repeated bells with --repeat-sound
+const bellSpacing = 300 * time.Millisecond
+
+// softFinishRounds and softFinishGap shape --soft-finish: round n rings the
+// bell n times, and each round starts softFinishGap after the one before.
+const (
+	softFinishRounds = 3
+	softFinishGap    = 2 * time.Second
+)
+
+// How long a per-phase completion message stays in the status line
+const phaseMessageDuration = 5 * time.Second
+
+// Step for scrubbing progress with the arrow keys while paused
+const scrubStep = 5 * time.Second
+
+// Time left after the --debug J key jumps ahead
+const debugJumpLeft = 10 * time.Second
+
+// A stopwatch is a timer that never runs out
+const stopwatchDuration = time.Duration(math.MaxInt64)
+
+// The final stretch of a timer that pulses with --finale
+const finaleLength = time.Minute
+
+// How long the finished screen blinks without interaction before going idle
+const idleFinishedAfter = 30 * time.Minute
+
+// Bounds for --finished-blink-rate
+const (
+	minFinishedBlinkRate = 100 * time.Millisecond
+	maxFinishedBlinkRate = 5 * time.Second
+)
+
+// Delay before quitting with --on-complete quit, so the finish is seen
+const quitDelay = 2 * time.Second
+
 type highlightMsg struct{}
+type flashMsg struct{}
+type bellMsg struct{}
+
+// pauseCheckMsg re-checks a pause for --pause-timeout. It carries the pause
+// start so checks left over from an earlier pause are ignored.
+type pauseCheckMsg struct {
+	pausedAt time.Time
+}
 
 // parseDuration parses the input string in "mm:ss" format into a time.Duration.
 // Returns an error if the format is invalid or out of bounds.
@@ -75,69 +324,366 @@ func parseDuration(input string) (time.Duration, error) {
 	return time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second, nil
 }
 
+// parseClock parses a local "hh:mm" time and returns its next occurrence after now.
+func parseClock(input string, now time.Time) (time.Time, error) {
+	clock, err := time.ParseInLocation("15:04", input, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected hh:mm", input)
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1) // Already passed today
+	}
+	return next, nil
+}
+
 // Init initializes the Bubble Tea model, starting the tick and blink commands.
 func (m model) Init() tea.Cmd {
-	return tea.Batch(tickCmd(), blinkCmd()) // Start ticking and blinking
+	cmds := []tea.Cmd{tickCmd(), blinkCmd(blinkRate), m.completeCmd()} // Start ticking and blinking
+	if m.opts.idlePause > 0 {
+		cmds = append(cmds, idleCheckCmd())
+	}
+	cmds = append(cmds, m.commandCmd()) // The first timer's plan commands, if any
+	if m.title != nil {
+		cmds = append(cmds, m.title.set(m))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles all messages (key presses, ticks, blinks, highlight timeouts) and updates the model state accordingly.
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m model) Update(msg tea.Msg) (_ tea.Model, cmd tea.Cmd) {
+	// Publish the resulting state once this message has been handled
+	defer func() {
+		m.publishMetrics()
+		if m.tmux != nil {
+			m.tmux.set(m.timerText())
+		}
+		if m.title != nil {
+			cmd = tea.Batch(cmd, m.title.set(m))
+		}
+		if m.recorder != nil {
+			m.recorder.record(m)
+		}
+	}()
+
+	// The summary screen is frozen; any key dismisses it and exits
+	if m.showingSummary {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			return m, tea.Quit
+		case controlMsg:
+			msg.reply <- "summary " + m.timerText() // Report, but don't act
+			if msg.command == quitCommand {
+				return m, tea.Quit // Replaced by a new instance; the session is already logged
+			}
+		}
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Handle key presses
 		switch msg.Type {
 		case tea.KeyCtrlC:
+			m.logSession()
 			return m, tea.Quit
+		case tea.KeyCtrlZ:
+			// Pause before handing control back to the shell so the suspended time is not counted
+			now := time.Now()
+			m.suspendedAt = now
+			if m.isRunning && !m.isPaused {
+				m.elapsedTime = now.Sub(m.startTime)
+				m.isPaused = true
+				m.pauseCount++
+				m.pausedAt = now
+				if m.opts.pauseTimeout > 0 {
+					return m, tea.Batch(tea.Suspend, pauseCheckCmd(m.pausedAt))
+				}
+			}
+			return m, tea.Suspend
+		}
+		if m.persisted {
+			// Only q leaves the persisted finished screen
+			if msg.String() == "q" {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.commanding {
+			return m.editCommand(msg) // Pasting into the command line is fine
+		}
+		if msg.Paste {
+			// Pasted text is not a key press; a stray q or r in it must not quit or reset
+			return m, nil
+		}
+		if msg.Type == tea.KeyRunes && len(msg.Runes) > 1 && msg.Runes[0] == ':' {
+			// Typed quickly, ':' and the command can arrive as one message
+			m.commanding = true
+			m.commandLine = ""
+			return m.editCommand(tea.KeyMsg{Type: tea.KeyRunes, Runes: msg.Runes[1:]})
 		}
 		now := time.Now()
+		if m.idle {
+			// Any key wakes the idle finished screen; r and q still act as usual
+			m.idle = false
+			m.finishedAt = now
+			if s := msg.String(); s != "r" && s != "q" {
+				return m, m.startBlink()
+			}
+		}
+		m.finishedAt = now // Interaction keeps the finished screen awake
 		switch msg.String() {
 		case "q":
+			if m.locked() {
+				return m.flashLocked(now)
+			}
 			// Highlight [q]uit and quit after highlightDuration
 			m.highlightKey = "q"
 			m.highlightUntil = now.Add(highlightDuration)
+			if m.isRunning && !m.isPaused {
+				m.elapsedTime = now.Sub(m.startTime) // Account up to the moment of quitting
+			}
+			m.logSession()
+			if m.opts.summary {
+				m.showingSummary = true
+				return m, nil
+			}
 			return m, tea.Batch(tea.Tick(highlightDuration, func(t time.Time) tea.Msg { return highlightMsg{} }), tea.Quit)
 		case "r":
+			if m.locked() {
+				return m.flashLocked(now)
+			}
 			// Highlight [r]eset and reset timer
-			wasRunning := m.isRunning && !m.isPaused
+			if m.inOvertime {
+				m.logSession() // Overtime ends here, so record it before starting over
+			}
 			m.isRunning = true
 			m.isPaused = false
+			m.inOvertime = false
+			m.quote = ""
+			m.waitUntil = time.Time{} // Start now rather than at the scheduled time
 			m.elapsedTime = 0
 			m.startTime = time.Now() // Reset start time for smooth progress
+			m.sessionStart = m.startTime
+			m.logged = false
+			m.loggedBlocks = 0
+			m.pauseCount = 0
+			m.pausedTime = 0
+			m.autoPaused = false
+			m.spoken = 0
+			m.goalReached = false
+			m.commandQueue = nil // Start now, skipping plan commands still holding the timer
+			m.commandFailed = ""
+			if m.opts.resetClearsLabel {
+				m.label = "" // A new task rather than another go at the same one
+			}
 			m.highlightKey = "r"
 			m.highlightUntil = now.Add(highlightDuration)
-			if wasRunning {
-				return m, tea.Tick(highlightDuration, func(t time.Time) tea.Msg { return highlightMsg{} })
-			} else {
-				return m, tea.Batch(tea.Tick(highlightDuration, func(t time.Time) tea.Msg { return highlightMsg{} }), tickCmd())
-			}
+			return m, tea.Batch(tea.Tick(highlightDuration, func(t time.Time) tea.Msg { return highlightMsg{} }), m.startTick(), m.completeCmd())
 		case "p":
+			if len(m.commandQueue) > 0 {
+				// Plan commands hold the timer; after a failure, p carries on with the rest
+				if m.commandFailed == "" {
+					return m, nil
+				}
+				return m.nextCommand()
+			}
 			// Highlight [p]ause or un[p]ause and toggle pause state after delay
 			m.highlightKey = "p"
 			m.highlightUntil = now.Add(highlightDuration)
 			m.pendingPauseToggle = true
 			return m, tea.Tick(highlightDuration, func(t time.Time) tea.Msg { return highlightMsg{} })
+		case "left", "right":
+			// Scrub progress while paused, keeping startTime consistent so resuming is continuous
+			if m.isRunning && m.isPaused {
+				step := scrubStep
+				if msg.String() == "left" {
+					step = -step
+				}
+				m.elapsedTime = min(max(m.elapsedTime+step, 0), m.totalTime)
+				m.startTime = time.Now().Add(-m.elapsedTime)
+			}
+		case "J":
+			// Debug only: skip ahead to the last seconds to exercise completion
+			if m.opts.debug && m.isRunning && !m.opts.stopwatch && m.totalTime > debugJumpLeft {
+				m.elapsedTime = max(m.elapsedTime, m.totalTime-debugJumpLeft)
+				m.startTime = now.Add(-m.elapsedTime)
+				return m, m.completeCmd()
+			}
+		case ".":
+			// Reveal the precise time for a moment, then redraw to drop it
+			m.revealUntil = now.Add(revealDuration)
+			return m, tea.Tick(revealDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+		case "m":
+			// Toggle sound and confirm the new state in the status area
+			m.muted = !m.muted
+			_ = saveSetting("mute", strconv.FormatBool(m.muted))
+			m.flashText = "🔊 sound on"
+			if m.muted {
+				m.flashText = "🔇 muted"
+			}
+			m.flashUntil = now.Add(flashDuration)
+			return m, tea.Tick(flashDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Tag the session with a --categories name
+			if len(m.opts.categories) > 0 {
+				return m.setCategory(int(msg.Runes[0] - '0'))
+			}
+		case "P":
+			// Cycle through the config presets while stopped or finished; Enter starts the one shown
+			if !m.isRunning && !m.inOvertime && m.waitUntil.IsZero() {
+				if len(m.presets) == 0 {
+					return m.flashError("no presets in config.toml")
+				}
+				if m.pickingPreset {
+					m.presetIndex = (m.presetIndex + 1) % len(m.presets)
+				} else {
+					m.pickingPreset, m.presetIndex = true, 0
+				}
+			}
+		case "enter":
+			if m.pickingPreset && !m.isRunning {
+				return m.startPreset()
+			}
+		case "esc":
+			m.pickingPreset = false
+		case ":":
+			// Open the command line (set, label, pause, resume, reset, quit)
+			m.commanding = true
+			m.commandLine = ""
+		case "t":
+			// Switch the center between remaining and elapsed time, remembering it in config.toml if there is one
+			m.showElapsed = !m.showElapsed
+			_ = saveSetting("show-elapsed", strconv.FormatBool(m.showElapsed))
+		case "y":
+			// Copy the displayed timer (and label, if any) to the clipboard
+			text := m.timerText()
+			if m.label != "" {
+				text += " " + m.label
+			}
+			return m, copyToClipboard(text)
+		}
+	case controlMsg:
+		// A command from `gopomotime ctl` or --control-fd
+		return m.handleControl(msg)
+	case controlFDErrMsg:
+		return m.flashError("control-fd: " + msg.err.Error())
+	case tea.ResumeMsg:
+		// Back from Ctrl+Z: overtime keeps counting, so skip over the suspended interval
+		if m.inOvertime && !m.suspendedAt.IsZero() {
+			m.startTime = m.startTime.Add(time.Since(m.suspendedAt))
+		}
+		m.suspendedAt = time.Time{}
+	case tea.WindowSizeMsg:
+		// Track the terminal size so View can fall back when the donut does not fit
+		m.termWidth = msg.Width
+		m.termHeight = msg.Height
+	case copiedMsg:
+		// Flash "Copied" on success, stay quiet on failure
+		if msg.err == nil {
+			m.flashText = "Copied"
+			m.flashUntil = time.Now().Add(flashDuration)
+			return m, tea.Tick(flashDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+		}
+	case idleMsg:
+		// Desktop idle time for --idle-pause
+		return m.handleIdle(msg)
+	case planCommandMsg:
+		// A plan command finished: go on, or hold the sequence until p or q
+		if len(m.commandQueue) == 0 {
+			break
+		}
+		if msg.err != nil {
+			m.commandFailed = msg.err.Error()
+			return m, nil
+		}
+		return m.nextCommand()
+	case execDoneMsg:
+		// Only a failing --exec command is worth interrupting for
+		if msg.err != nil {
+			return m.flashError("exec: " + msg.err.Error())
+		}
+	case bellMsg:
+		// A repeat of the completion bell (--repeat-sound, --soft-finish), skipped if muted since
+		if m.soundAllowed() {
+			return m, bellCmd()
+		}
+	case flashMsg:
+		// Clear the flash once it has expired
+		if !time.Now().Before(m.flashUntil) {
+			m.flashText = ""
+			m.flashUntil = time.Time{}
 		}
 	case tickMsg:
+		if !m.waitUntil.IsZero() {
+			// Waiting for the scheduled start (--at)
+			if now := time.Now(); !now.Before(m.waitUntil) {
+				m.waitUntil = time.Time{}
+				m.isRunning = true
+				m.startTime = now
+				m.sessionStart = now
+				m.queueCommands()
+				return m, tea.Batch(tickCmd(), m.completeCmd(), m.commandCmd())
+			}
+			// Nothing moves while waiting, so check in at the slower blink cadence
+			return m, waitTickCmd(m.waitUntil)
+		}
+		if m.timing != nil && (m.inOvertime || (m.isRunning && !m.isPaused)) {
+			m.timing.record(time.Now())
+		}
+		if m.inOvertime {
+			// Keep counting past totalTime until reset or quit
+			m.elapsedTime = time.Now().Sub(m.startTime)
+			return m, tickCmd()
+		}
 		// Handle timer tick for smooth progress
-		if m.isRunning && !m.isPaused && m.elapsedTime < m.totalTime {
+		if m.isRunning && !m.isPaused {
 			// Use wall clock time for smooth progress
 			now := time.Now()
 			m.elapsedTime = now.Sub(m.startTime)
 			if m.elapsedTime >= m.totalTime {
-				m.isRunning = false
-				m.isPaused = false
-				m.elapsedTime = m.totalTime // Ensure no rollover
+				m.ticking = false // This loop ends here; complete starts another if the timer goes on
+				return m, m.complete()
 			}
-			if m.isRunning {
-				return m, tickCmd()
+			var speech tea.Cmd
+			if m.opts.speak && !m.opts.stopwatch {
+				speech = m.announce(m.totalTime - m.elapsedTime)
+			}
+			if m.inFinale() {
+				return m, tea.Batch(tickCmd(), m.startBlink(), speech) // Pulse through the final minute
+			}
+			return m, tea.Batch(tickCmd(), speech)
+		}
+		// Paused or finished: let the fast tick lapse. Unpausing and resetting
+		// restart it, and a finished timer is kept alive by the blink loop.
+		m.ticking = false
+		if m.timing != nil {
+			m.timing.pause()
+		}
+		return m, nil
+	case completeMsg:
+		// The exact end of the timer, unless the timing has changed since it was scheduled
+		if m.isRunning && !m.isPaused && !m.inOvertime && m.waitUntil.IsZero() &&
+			msg.startTime.Equal(m.startTime) && msg.totalTime == m.totalTime {
+			m.elapsedTime = time.Now().Sub(m.startTime)
+			if m.elapsedTime >= m.totalTime {
+				return m, m.complete()
 			}
+			return m, m.completeCmd() // Woken a little early; try again
 		}
-		return m, blinkCmd() // Continue blinking when finished
 	case blinkMsg:
-		// Handle blinking for finished timer
+		// Handle blinking for finished timer (and the --finale countdown)
+		m.blinking = false
 		m.blink = !m.blink
-		if !m.isRunning && m.elapsedTime >= m.totalTime {
-			return m, blinkCmd() // Keep blinking only when finished
+		m.blinkCount++
+		if !m.isRunning && m.elapsedTime >= m.totalTime && !m.inOvertime && time.Since(m.finishedAt) > idleFinishedAfter {
+			// Stop animating a finished screen nobody is looking at
+			m.idle = true
+			return m, nil
+		}
+		if (!m.isRunning && m.elapsedTime >= m.totalTime) || m.inFinale() {
+			return m, m.startBlink() // Keep blinking only when finished or in the final minute
 		}
 	case highlightMsg:
 		// Clear highlight after duration
@@ -145,246 +691,1699 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.highlightUntil = time.Time{}
 		// If a pause toggle is pending, perform it now
 		if m.pendingPauseToggle {
+			m.autoPaused = false // Paused or unpaused by hand from here on
 			if m.isRunning {
 				m.isPaused = !m.isPaused
-				if !m.isPaused {
+				if m.isPaused {
+					m.pausedAt = time.Now()
+					// Catch up on time since the last tick so resuming picks up exactly here
+					m.elapsedTime = m.pausedAt.Sub(m.startTime)
+					m.pauseCount++
+					if m.opts.pauseTimeout > 0 {
+						m.pendingPauseToggle = false
+						return m, pauseCheckCmd(m.pausedAt)
+					}
+				} else {
 					// When unpausing, adjust startTime so elapsedTime is continuous
+					m.endPause(time.Now())
 					m.startTime = time.Now().Add(-m.elapsedTime)
 					m.pendingPauseToggle = false
-					return m, tickCmd()
+					return m, tea.Batch(m.startTick(), m.completeCmd())
 				}
-			} else {
+			} else if !m.inOvertime {
 				m.isRunning = true
 				m.isPaused = false
 				m.startTime = time.Now().Add(-m.elapsedTime)
+				m.pendingPauseToggle = false
+				if !m.waitUntil.IsZero() {
+					// Start early; the waiting tick loop carries on as the timer tick
+					m.waitUntil = time.Time{}
+					m.sessionStart = m.startTime
+					m.queueCommands()
+					return m, tea.Batch(m.completeCmd(), m.commandCmd())
+				} else if m.elapsedTime < m.totalTime {
+					if m.elapsedTime == 0 {
+						m.sessionStart = m.startTime // First start (--clock-then-donut), not a restart
+						m.queueCommands()
+					}
+					return m, tea.Batch(m.startTick(), m.completeCmd(), m.commandCmd()) // Restart ticking after a stop
+				}
 			}
 			m.pendingPauseToggle = false
 		}
+	case pauseCheckMsg:
+		// Ignore checks from a pause that has since ended
+		if !m.isRunning || !m.isPaused || !msg.pausedAt.Equal(m.pausedAt) {
+			break
+		}
+		if time.Since(m.pausedAt) < m.opts.pauseTimeout {
+			return m, pauseCheckCmd(m.pausedAt) // Keep the countdown on screen fresh
+		}
+		if m.opts.pauseTimeoutAction == "resume" {
+			m.endPause(time.Now())
+			m.isPaused = false
+			m.startTime = time.Now().Add(-m.elapsedTime)
+			return m, tea.Batch(m.startTick(), m.completeCmd())
+		}
+		// Stop the abandoned session and record only the time actually worked
+		m.logSession() // While still paused, so the pause is counted
+		m.isRunning = false
+		m.isPaused = false
 	}
 	return m, nil
 }
 
-// View renders the TUI, including the donut, timer, and status/controls, with proper centering and highlighting.
-func (m model) View() string {
-	// Calculate remaining time for the timer
-	remaining := m.totalTime - m.elapsedTime
-	if remaining < 0 {
-		remaining = 0 // Prevent negative display
+// complete handles the current timer reaching zero: it records the session, then
+// moves on to the next timer or into the finished state. It returns the follow-up commands.
+func (m *model) complete() tea.Cmd {
+	var sound tea.Cmd
+	if m.planIndex+1 == len(m.plan) || m.transitionSounds(m.plan[m.planIndex].kind) {
+		sound = m.completionSound()
 	}
-	minutes := int(remaining.Minutes()) % 60
-	seconds := int(remaining.Seconds()) % 60
-	timer := fmt.Sprintf("%02d:%02d", minutes, seconds)
-
-	// Calculate progress for the donut (0.0 to 1.0), use wall clock for smoothness
-	progress := 0.000
-	if m.totalTime > 0 {
-		progress = float64(m.elapsedTime) / float64(m.totalTime)
-		if progress > 1.000 {
-			progress = 1.000 // Cap progress at 100%
-		}
+	if m.opts.exec != "" {
+		sound = tea.Batch(sound, execCmd(m.opts.exec, m.label, m.totalTime))
 	}
-
-	// Draw the ASCII donut with progress and timer
-	circle := drawCircle(progress, timer)
-
-	// Build the status/control text block
-	var status string
-	if !m.isRunning {
-		if m.elapsedTime >= m.totalTime {
-			// Timer finished: show blinking green message and controls
-			finishedText := "Timer finished!"
-			padding := (width - len(finishedText)) / 2 // 7 spaces
-			if m.blink {
-				finishedText = strings.Repeat(" ", padding) + greenStyle.Render(finishedText) + strings.Repeat(" ", padding)
-			} else {
-				finishedText = strings.Repeat(" ", width) // Full width blank for centering
-			}
-			controls := " [q]uit [r]eset [p]ause"
-			controlsPadding := (width - len(controls)) / 2 // 4 spaces
-			controls = strings.Repeat(" ", controlsPadding) + controls
-			status = finishedText + "\n" + controls
-		} else {
-			// Timer stopped: show stopped message and controls
-			status = "Timer stopped. \n    [q]uit [r]eset [p]ause"
-		}
-	} else if m.isPaused {
-		// Timer paused: show paused message and controls
-		status = "Timer paused. \n    [q]uit [r]eset un[p]ause"
-	} else {
-		// Timer running: show only controls
-		status = " \n    [q]uit [r]eset [p]ause"
+	if m.sysLog != nil {
+		sound = tea.Batch(sound, syslogCmd(m.sysLog, m.label, m.totalTime))
 	}
-
-	// Center status text within 29-column width, with highlight if needed
-	statusLines := strings.Split(status, "\n")
-	for i, line := range statusLines {
-		// Only center and highlight control/status lines, not the blinking finished text (already padded)
-		if !(i == 0 && !m.isRunning && m.elapsedTime >= m.totalTime) {
-			// Highlight the relevant key if pressed recently
-			if m.highlightKey != "" && time.Now().Before(m.highlightUntil) {
-				if m.highlightKey == "q" && strings.Contains(line, "[q]uit") {
-					// Highlight [q]uit, pad to same width
-					h := highlightStyle.Render("[q]uit")
-					pad := len("[q]uit") - len([]rune("[q]uit")) + len([]rune(h)) - len(h)
-					line = strings.Replace(line, "[q]uit", h+strings.Repeat(" ", pad), 1)
-				} else if m.highlightKey == "r" && strings.Contains(line, "[r]eset") {
-					// Highlight [r]eset, pad to same width
-					h := highlightStyle.Render("[r]eset")
-					pad := len("[r]eset") - len([]rune("[r]eset")) + len([]rune(h)) - len(h)
-					line = strings.Replace(line, "[r]eset", h+strings.Repeat(" ", pad), 1)
-				} else if m.highlightKey == "p" {
-					// Highlight [p]ause and/or un[p]ause, pad to same width
-					if strings.Contains(line, "[p]ause") && !strings.Contains(line, "un[p]ause") {
-						h := highlightStyle.Render("[p]ause")
-						pad := len("[p]ause") - len([]rune("[p]ause")) + len([]rune(h)) - len(h)
-						line = strings.Replace(line, "[p]ause", h+strings.Repeat(" ", pad), 1)
-					}
-					if strings.Contains(line, "un[p]ause") {
-						h := highlightStyle.Render("un[p]ause")
-						pad := len("un[p]ause") - len([]rune("un[p]ause")) + len([]rune(h)) - len(h)
-						line = strings.Replace(line, "un[p]ause", h+strings.Repeat(" ", pad), 1)
-					}
-				}
-			}
-			// Center the line using the printable width (strip ANSI codes)
-			plainLine := stripANSI(line)
-			padding := (width - len([]rune(strings.TrimSpace(plainLine)))) / 2
-			if padding < 0 {
-				padding = 0
-			}
-			statusLines[i] = strings.Repeat(" ", padding) + line
-		}
+	if m.opts.speak && m.soundAllowed() {
+		sound = tea.Batch(sound, speakCmd("Time's up"))
+	}
+	if text := m.phaseMessage(); text != "" {
+		// Announce the transition in the status line for a while
+		m.flashText = truncateText(text, width)
+		m.flashUntil = time.Now().Add(phaseMessageDuration)
+		sound = tea.Batch(sound, tea.Tick(phaseMessageDuration, func(t time.Time) tea.Msg { return flashMsg{} }))
+	}
+	if m.metrics != nil {
+		m.metrics.incCompleted()
+	}
+	if m.planIndex+1 < len(m.plan) {
+		// Move straight on to the next timer in the plan
+		m.elapsedTime = m.totalTime
+		m.logSession()
+		m.startSegment(m.planIndex + 1)
+		m.queueCommands()
+		goal := m.checkDailyGoal()
+		return tea.Batch(sound, goal, m.startTick(), m.completeCmd(), m.commandCmd())
 	}
-	centeredStatus := strings.Join(statusLines, "\n")
 
-	// Add left padding to shift entire block left for donut and status
-	leftPadding := strings.Repeat(" ", 4)
-	output := strings.Join(strings.Split(circle, "\n"), "\n"+leftPadding) + "\n" + leftPadding + centeredStatus
-	return circleStyle.Render(leftPadding + output)
+	m.isRunning = false
+	m.isPaused = false
+	m.finishedAt = time.Now()
+	m.silenced = !m.soundAllowed()
+	m.blink = true // Show the finished message straight away, however slow --finished-blink-rate is
+	if m.opts.snapshotPath != "" {
+		_ = writeSnapshot(m.opts.snapshotPath, *m) // Best-effort, like the history log
+	}
+	switch m.opts.onComplete {
+	case "loop":
+		// Start the whole sequence over
+		m.elapsedTime = m.totalTime
+		m.logSession()
+		m.startSegment(0)
+		m.queueCommands()
+		m.goalReached = false // A new run; the exact count keeps today's goal from firing twice
+		goal := m.checkDailyGoal()
+		return tea.Batch(sound, goal, m.startTick(), m.completeCmd(), m.commandCmd())
+	case "quit":
+		m.elapsedTime = m.totalTime
+		m.logSession()
+		sound = tea.Batch(sound, m.checkDailyGoal())
+		return tea.Batch(sound, m.startBlink(), tea.Tick(quitDelay, func(t time.Time) tea.Msg { return tea.QuitMsg{} }))
+	}
+	if m.opts.scheduleDaily != "" {
+		// Log the day's run and wait for the same time tomorrow
+		m.elapsedTime = m.totalTime
+		m.logSession()
+		sound = tea.Batch(sound, m.checkDailyGoal())
+		m.updateStreak()
+		m.waitForNextRun()
+		return tea.Batch(sound, m.startTick()) // A running loop slows to the wait cadence by itself
+	}
+	m.quote = pickQuote(m.quotes, m.rng)
+	if m.opts.overtime {
+		m.inOvertime = true
+		return tea.Batch(sound, m.startTick(), m.startBlink())
+	}
+	m.elapsedTime = m.totalTime // Ensure no rollover
+	m.logSession()
+	sound = tea.Batch(sound, m.checkDailyGoal())
+	m.updateStreak()
+	m.updateTrend()
+	if m.opts.persistSummary {
+		// Leave the result in the normal buffer, where it outlives the program
+		m.persisted = true
+		return tea.Batch(sound, tea.ExitAltScreen)
+	}
+	return tea.Batch(sound, m.startBlink()) // Blink while finished
 }
 
-// tickCmd returns a Bubble Tea command that sends a tickMsg every second.
-func tickCmd() tea.Cmd {
-	return tea.Tick(tickRate, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+// waitForNextRun rewinds the plan to its first timer and waits for the next
+// occurrence of the --schedule-daily time.
+func (m *model) waitForNextRun() {
+	m.startSegment(0)
+	m.goalReached = false // Tomorrow's run has its own goal
+	m.isRunning = false
+	m.waitUntil, _ = parseClock(m.opts.scheduleDaily, time.Now()) // Validated at startup
 }
 
-// blinkCmd returns a Bubble Tea command that sends a blinkMsg every blinkRate interval.
-func blinkCmd() tea.Cmd {
-	return tea.Tick(blinkRate, func(t time.Time) tea.Msg {
-		return blinkMsg(t)
-	})
+// transitionSounds reports whether --transition-sound wants a sound when a
+// phase of this kind ends and the sequence moves on. The end of the last
+// timer always sounds.
+func (m model) transitionSounds(kind phaseKind) bool {
+	switch m.opts.transitionSound {
+	case "work-only":
+		return kind == phaseWork
+	case "break-only":
+		return kind == phaseBreak
+	case "none":
+		return false
+	}
+	return true
 }
 
-// drawCircle creates a 13x29 ASCII donut with progress and timer in the center.
-// The donut fills clockwise as time elapses.
-func drawCircle(progress float64, timer string) string {
-	// ASCII donut template, 13 rows x 29 columns
-	donutTemplate := []string{
-		"          *********          ",
-		"      *****************      ",
-		"    *********************    ",
-		"  **********     **********  ",
-		" ********           ******** ",
-		" ******               ****** ",
-		" ******     mm:ss     ****** ",
-		" ******               ****** ",
-		" *******             ******* ",
-		"  **********     **********  ",
-		"    *********************    ",
-		"      *****************      ",
-		"          *********          ",
-	}
+// silencedMark follows the finished message when its alert was suppressed.
+const silencedMark = "🔕"
 
-	centerX, centerY := float64(width/2), float64(height/2) // Center of donut
-	totalSegments := 120                                    // Number of progress segments for smoothness
-	lines := make([]string, height)
+// soundAllowed reports whether audible alerts may play right now.
+func (m model) soundAllowed() bool {
+	return !m.muted
+}
 
-	// Loop over each row of the donut
-	for y := 0; y < height; y++ {
-		line := ""
-		timerStart := (width - len(timer)) / 2 // Center timer horizontally
-		timerEnd := timerStart + len(timer)
-		// Loop over each character in the row
-		for x, char := range donutTemplate[y] {
-			if char == '*' {
-				// Calculate angle for progress marker (0 at 12 o'clock, clockwise)
-				dx := float64(x) - centerX
-				dy := float64(y) - centerY
-				angle := math.Atan2(dy, dx) + math.Pi/2 // Start at 12 o'clock
-				if angle < 0 {
-					angle += 2 * math.Pi
-				}
-				segment := int((angle / (2 * math.Pi)) * float64(totalSegments))
-				// Fill with white for elapsed, red for remaining
-				if segment < int(progress*float64(totalSegments)) {
-					line += whiteStyle.Render("*")
-				} else {
-					line += redStyle.Render("*")
-				}
-			} else if y == height/2 && x >= timerStart && x < timerEnd {
-				// Place the actual timer in the center row
-				line += whiteStyle.Render(string(timer[x-timerStart]))
-			} else {
-				line += " "
-			}
+// completionSound returns the command that alerts for a finished timer, or nil when muted.
+// Work and break phases can each have their own sound command; others ring the bell.
+func (m model) completionSound() tea.Cmd {
+	if !m.soundAllowed() {
+		return nil
+	}
+	command := ""
+	switch m.plan[m.planIndex].kind {
+	case phaseWork:
+		command = m.opts.workEndSound
+	case phaseBreak:
+		command = m.opts.breakEndSound
+	}
+	if command == "" {
+		// Ring now, then schedule any repeats so the TUI keeps running in between
+		cmds := []tea.Cmd{bellCmd()}
+		for _, at := range m.bellSchedule()[1:] {
+			cmds = append(cmds, tea.Tick(at, func(t time.Time) tea.Msg { return bellMsg{} }))
 		}
-		lines[y] = line
+		return tea.Batch(cmds...)
 	}
-
-	return strings.Join(lines, "\n")
+	return shellCmd(command)
 }
 
-// stripANSI removes ANSI escape codes for accurate width calculation when centering highlighted text.
-func stripANSI(str string) string {
-	in := false
-	out := make([]rune, 0, len(str))
-	for _, r := range str {
-		if r == 27 { // ESC
-			in = true
-			continue
-		}
-		if in {
-			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-				in = false
+// bellSchedule returns when each completion bell rings, relative to the first:
+// --repeat-sound rings spaced evenly, or with --soft-finish, rounds of one,
+// two, then three rings that start quietly and build up.
+func (m model) bellSchedule() []time.Duration {
+	var at []time.Duration
+	if m.opts.softFinish {
+		for round := 1; round <= softFinishRounds; round++ {
+			start := time.Duration(round-1) * softFinishGap
+			for i := range round {
+				at = append(at, start+time.Duration(i)*bellSpacing)
 			}
-			continue
 		}
-		out = append(out, r)
+		return at
 	}
-	return string(out)
+	for i := range m.opts.repeatSound {
+		at = append(at, time.Duration(i)*bellSpacing)
+	}
+	return at
 }
 
-// main is the entry point. It parses arguments, initializes the model, and runs the Bubble Tea program.
-func main() {
-	// Check for correct argument count
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: gopomotime mm:ss")
-		os.Exit(1)
+// phaseMessage returns the message configured for the end of the current phase, if any.
+func (m model) phaseMessage() string {
+	switch m.plan[m.planIndex].kind {
+	case phaseWork:
+		return m.opts.workEndMessage
+	case phaseBreak:
+		return m.opts.breakEndMessage
 	}
+	return ""
+}
 
-	// Parse the duration argument
-	duration, err := parseDuration(os.Args[1])
-	if err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
+// progress returns the fraction of the current timer elapsed, from 0.0 to 1.0.
+func (m model) progress() float64 {
+	if m.opts.stopwatch {
+		return float64(m.elapsedTime%time.Minute) / float64(time.Minute) // One sweep per minute
 	}
+	if m.totalTime <= 0 {
+		return 0
+	}
+	return math.Min(float64(m.elapsedTime)/float64(m.totalTime), 1) // Cap progress at 100%
+}
 
-	// Initialize the model with the parsed duration
-	m := model{
-		totalTime:   duration,
-		elapsedTime: 0,
-		isRunning:   true, // Start timer immediately
-		isPaused:    false,
-		blink:       true,       // Start with text visible
-		startTime:   time.Now(), // For smooth progress
+// locked reports whether --strict is holding [q]uit and [r]eset back while a timer runs.
+func (m model) locked() bool {
+	return m.opts.strict && m.isRunning
+}
+
+// flashLocked tells the user that q and r are disabled until the timer finishes.
+func (m model) flashLocked(now time.Time) (tea.Model, tea.Cmd) {
+	m.flashText = "🔒 " + msgs.Locked
+	m.flashUntil = now.Add(flashDuration)
+	return m, tea.Tick(flashDuration, func(t time.Time) tea.Msg { return flashMsg{} })
+}
+
+// controls returns the control line, replacing [q]uit and [r]eset with a lock marker under --strict.
+func (m model) controls(paused bool) string {
+	if !m.locked() {
+		return msgs.controls(paused)
+	}
+	pause := msgs.Pause
+	if paused {
+		pause = msgs.Unpause
 	}
+	return "🔒 " + pause
+}
 
-	// Start the Bubble Tea program with alternate screen
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+// controlsLine returns the control line as appended under the status, or
+// nothing with --hide-controls.
+func (m model) controlsLine(paused bool) string {
+	if m.opts.hideControls {
+		return ""
+	}
+	return "\n    " + m.controls(paused)
+}
+
+// timerText formats the remaining time as "MM:SS", or the overtime as "+MM:SS".
+func (m model) timerText() string {
+	if m.opts.stopwatch {
+		if m.opts.precise {
+			return formatPrecise(m.elapsedTime)
+		}
+		return formatClock(m.elapsedTime)
+	}
+	remaining := m.totalTime - m.elapsedTime
+	if remaining < 0 && !m.inOvertime {
+		remaining = 0 // Prevent negative display
+	}
+	if m.opts.roundDisplay {
+		remaining = remaining.Round(time.Second) // Nearest second instead of truncating
+	}
+	return formatRemaining(remaining)
+}
+
+// formatRemaining formats the time left as "MM:SS", or time past zero as
+// "+MM:SS". The donut, title, tmux and ctl outputs all go through it.
+func formatRemaining(remaining time.Duration) string {
+	if remaining < 0 {
+		return "+" + formatClock(-remaining)
+	}
+	return formatClock(remaining)
+}
+
+// now returns the current time for rendering, from m.clock when one is set.
+func (m model) now() time.Time {
+	if m.clock != nil {
+		return m.clock()
+	}
+	return time.Now()
+}
+
+// displayTime is the time shown in the donut: the timer text, or the elapsed
+// time when the t key has switched a countdown to counting up.
+func (m model) displayTime() string {
+	if m.now().Before(m.revealUntil) && !m.opts.stopwatch {
+		// Briefly to the tenth of a second, after pressing '.'
+		switch {
+		case m.inOvertime:
+			return "+" + formatTenths(m.elapsedTime-m.totalTime)
+		case m.showElapsed:
+			return formatTenths(min(m.elapsedTime, m.totalTime))
+		}
+		return formatTenths(max(m.totalTime-m.elapsedTime, 0))
+	}
+	if m.showElapsed && !m.opts.stopwatch && !m.inOvertime {
+		return formatClock(min(m.elapsedTime, m.totalTime))
+	}
+	return m.timerText()
+}
+
+// formatPrecise formats a duration as "ss.cc", or "m:ss.cc" from a minute up.
+func formatPrecise(d time.Duration) string {
+	hundredths := int(d/(10*time.Millisecond)) % 100
+	seconds := int(d.Seconds()) % 60
+	if d < time.Minute {
+		return fmt.Sprintf("%02d.%02d", seconds, hundredths)
+	}
+	return fmt.Sprintf("%d:%02d.%02d", int(d.Minutes()), seconds, hundredths)
+}
+
+// formatTenths formats a duration as "MM:SS.t", for the '.' key.
+func formatTenths(d time.Duration) string {
+	return fmt.Sprintf("%s.%d", formatClock(d), int(d/(100*time.Millisecond))%10)
+}
+
+// formatClock formats a duration as "MM:SS". Minutes are not wrapped at an
+// hour, so long overtimes read e.g. "75:00" rather than "15:00".
+func formatClock(d time.Duration) string {
+	minutes := int(d.Minutes())
+	seconds := int(d.Seconds()) % 60
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+// showLabel reports whether a label line is drawn above the donut.
+func (m model) showLabel() bool {
+	return m.label != "" || m.category != "" || len(m.plan) > 1
+}
+
+// tooSmall reports whether the known terminal size cannot fit the donut and status lines.
+func (m model) tooSmall() bool {
+	if m.termWidth == 0 && m.termHeight == 0 {
+		return false // Size not known yet
+	}
+	rows := height + 2 + len(m.quoteLines()) // Donut plus status, quote and controls
+	if m.timing != nil {
+		rows++ // --debug-timing line
+	}
+	if m.showLabel() {
+		rows += len(m.labelLines())
+	}
+	return m.termWidth < width+4 || m.termHeight < rows
+}
+
+// summaryLines lists the session's figures for the --summary screen and --persist-summary.
+func (m model) summaryLines() []string {
+	planned := formatClock(m.totalTime)
+	if m.opts.stopwatch {
+		planned = "-"
+	}
+	status := msgs.StoppedEarly
+	if m.elapsedTime >= m.totalTime {
+		status = msgs.Completed
+	}
+	label := m.label
+	if label == "" {
+		label = "-"
+	}
+
+	rows := [][2]string{
+		{msgs.SummaryLabel, label},
+		{msgs.SummaryPlanned, planned},
+		{msgs.SummaryActual, formatClock(m.elapsedTime)},
+		{msgs.SummaryStatus, status},
+		{msgs.SummaryPauses, strconv.Itoa(m.pauseCount)},
+	}
+	if m.opts.score {
+		rows = append(rows, [2]string{msgs.SummaryScore, strconv.Itoa(m.focusScore())})
+	}
+	// Line the values up one space past the longest row name
+	column := 0
+	for _, row := range rows {
+		column = max(column, lipgloss.Width(row[0])+1)
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = row[0] + strings.Repeat(" ", column-lipgloss.Width(row[0])) + truncateText(row[1], width-column)
+	}
+	return lines
+}
+
+// summaryView renders the end-of-session review shown by --summary.
+func (m model) summaryView() string {
+	lines := append([]string{msgs.SummaryTitle, ""}, m.summaryLines()...)
+	lines = append(lines, "", msgs.AnyKeyToExit)
+	leftPadding := strings.Repeat(" ", 4)
+	return leftPadding + strings.Join(lines, "\n"+leftPadding)
+}
+
+// persistedView renders the finished screen for --persist-summary, in the
+// normal buffer: the donut without blinking or controls, then the summary.
+// The last frame drawn stays in the scrollback after exiting.
+func (m model) persistedView() string {
+	finished := m
+	finished.persisted = false
+	finished.blink = true
+	finished.opts.hideControls = true
+	finished.flashText = ""
+	lines := m.summaryLines()
+	if m.quitting {
+		lines = append(lines, "") // Bubble Tea clears the last line on exit
+	} else {
+		lines = append(lines, "", msgs.QToExit)
+	}
+	leftPadding := strings.Repeat(" ", 4)
+	return finished.View() + "\n\n" + leftPadding + strings.Join(lines, "\n"+leftPadding)
+}
+
+// compactView renders a one-line "mm:ss" display for terminals too small for the donut.
+func (m model) compactView() string {
+	line := localizeDigits(m.displayTime())
+	if m.label != "" {
+		line += " " + m.label
+	}
+	if m.isRunning && m.isPaused {
+		line += " " + msgs.PausedTag
+	} else if !m.isRunning && m.elapsedTime >= m.totalTime {
+		line += " " + msgs.FinishedTag
+	}
+	return truncateText(line, m.termWidth) + "\n" + truncateText(msgs.TooSmall, m.termWidth)
+}
+
+// View renders the TUI, including the donut, timer, and status/controls, with proper centering and highlighting.
+func (m model) View() string {
+	if m.showingSummary {
+		return m.summaryView()
+	}
+	if m.persisted {
+		return m.persistedView()
+	}
+
+	// Fall back to a single line when the terminal cannot fit the donut
+	if m.tooSmall() {
+		return m.compactView()
+	}
+
+	// Format the remaining time for the timer
+	timer := m.displayTime()
+
+	// Calculate progress for the donut (0.0 to 1.0), use wall clock for smoothness
+	progress := m.progress()
+
+	// Draw the ASCII donut with progress and timer
+	timerStyle := whiteStyle
+	if m.inOvertime {
+		timerStyle = overtimeStyle
+	}
+	ring := ringOptions{timerStyle: timerStyle, paused: m.isRunning && m.isPaused, secondHand: -1, celebrate: -1, comet: m.opts.comet, finale: m.inFinale(), pulse: m.blink, braille: m.opts.braille, breath: m.breathLevel()}
+	if m.opts.minuteMarks && !m.opts.stopwatch {
+		// One division per whole minute, skipped when too dense to tell apart
+		if minutes := int(m.totalTime / time.Minute); minutes >= 2 && minutes <= maxMinuteMarks {
+			ring.marks = minutes
+		}
+	}
+	if m.opts.onComplete == "celebrate" && !m.isRunning && m.elapsedTime >= m.totalTime {
+		ring.celebrate = m.blinkCount // Rotate the celebration colors on every blink
+	} else if m.opts.finishedAnim != "none" && !m.isRunning && m.elapsedTime >= m.totalTime && !m.idle {
+		ring.finishedAnim = m.opts.finishedAnim
+		ring.animFrame = m.blinkCount // One step per blink; the loop stops on reset and when idle
+	}
+	if m.goalReached && !m.isRunning && m.elapsedTime >= m.totalTime && !m.idle {
+		ring.finishedAnim = "sparkle" // The --daily-goal celebration
+		ring.animFrame = m.blinkCount
+	}
+	if m.opts.secondHand {
+		// One sweep per second of elapsed time
+		ring.secondHand = float64(m.elapsedTime%time.Second) / float64(time.Second)
+	}
+	var circle string
+	if m.showBigClock() {
+		circle = bigClock(timer, timerStyle) // Block digits are drawn in ASCII shapes
+	} else {
+		circle = drawCircle(progress, localizeDigits(timer), ring)
+	}
+
+	// Build the status/control text block
+	var status string
+	controls := m.controlsLine(false)
+	if !m.waitUntil.IsZero() {
+		// Scheduled start: show when the timer will begin
+		status = msgs.WaitingUntil + " " + m.waitUntil.Format("15:04") + "…" + controls
+	} else if !m.isRunning {
+		if m.elapsedTime >= m.totalTime {
+			// Timer finished: show blinking green message and controls
+			finishedText := msgs.Finished
+			if m.opts.onComplete == "celebrate" {
+				finishedText = msgs.AllDone
+			}
+			if monochrome {
+				finishedText = "*** " + finishedText + " ***" // Visible without color
+			} else {
+				finishedText = activePalette.finishedMark + finishedText
+			}
+			mark := ""
+			if m.silenced {
+				mark = " " + silencedMark // Steady, so it explains the missing bell while the text blinks
+			}
+			padding := max(0, (width-lipgloss.Width(finishedText+mark))/2) // 7 spaces for "Timer finished!"
+			if m.blink {
+				finishedText = strings.Repeat(" ", padding) + greenStyle.Render(finishedText) + dimWhiteStyle.Render(mark) + strings.Repeat(" ", padding)
+			} else if mark != "" {
+				finishedText = strings.Repeat(" ", padding+lipgloss.Width(finishedText)) + dimWhiteStyle.Render(mark) + strings.Repeat(" ", padding)
+			} else {
+				finishedText = strings.Repeat(" ", width) // Full width blank for centering
+			}
+			status = finishedText + controls
+			if m.idle {
+				status = msgs.FinishedIdle + "\n    " + msgs.RestartHint + controls
+			} else if extra := slices.Concat(m.goalLines(), m.streakLines(), m.trendLines(), m.linkLines(), m.quoteLines()); len(extra) > 0 {
+				// Link and quote lines carry the same 4-space margin as the controls
+				status = finishedText + "\n    " + strings.Join(extra, "\n    ") + controls
+			}
+		} else if m.showBigClock() {
+			// Not started yet (--clock-then-donut)
+			status = msgs.PressToStart + controls
+		} else {
+			// Timer stopped: show stopped message and controls
+			status = msgs.Stopped + controls
+		}
+	} else if len(m.commandQueue) > 0 {
+		// Held while the plan's commands run; a failure replaces the controls with its prompt
+		status = m.commandStatus()
+		if m.commandFailed == "" {
+			status += controls
+		}
+	} else if m.isPaused {
+		// Timer paused: show paused message (with any auto-action countdown) and controls
+		controls = m.controlsLine(true)
+		status = msgs.Paused + controls
+		if m.autoPaused {
+			status = msgs.PausedAway + controls
+		}
+		if m.opts.pauseTimeout > 0 {
+			left := m.opts.pauseTimeout - m.now().Sub(m.pausedAt)
+			if left < 0 {
+				left = 0
+			}
+			countdown := msgs.PauseStops
+			if m.opts.pauseTimeoutAction == "resume" {
+				countdown = msgs.PauseResumes
+			}
+			status = fmt.Sprintf("%s %02d:%02d", countdown, int(left.Minutes()), int(left.Seconds())%60) + controls
+		}
+	} else if m.opts.breathe && !m.inOvertime {
+		// Timer running with --breathe: cue the breath above the controls
+		status = m.breathText() + controls
+	} else {
+		// Timer running: show only controls
+		status = " " + controls
+	}
+
+	// A pending flash message replaces the status line
+	flashing := m.flashText != "" && m.now().Before(m.flashUntil)
+	statusLines := strings.Split(status, "\n")
+	if flashing {
+		statusLines[0] = m.flashText
+	}
+	if m.commanding {
+		statusLines[0] = ":" + m.commandLine + highlightStyle.Render("_")
+	}
+	picking := m.pickingPreset && !m.isRunning
+	if picking && !m.commanding {
+		statusLines[0] = m.presetLine()
+	}
+
+	// Center status text within 29-column width, with highlight if needed
+	for i, line := range statusLines {
+		// Only center and highlight control/status lines, not the blinking finished text (already padded)
+		if !(i == 0 && !flashing && !m.commanding && !picking && !m.idle && !m.isRunning && m.elapsedTime >= m.totalTime) {
+			// Highlight the relevant key if pressed recently
+			if m.highlightKey != "" && m.now().Before(m.highlightUntil) {
+				switch m.highlightKey {
+				case "q":
+					line = strings.Replace(line, msgs.Quit, markKey(msgs.Quit), 1)
+				case "r":
+					line = strings.Replace(line, msgs.Reset, markKey(msgs.Reset), 1)
+				case "p":
+					// Highlight un[p]ause when paused, [p]ause otherwise
+					if strings.Contains(line, msgs.Unpause) {
+						line = strings.Replace(line, msgs.Unpause, markKey(msgs.Unpause), 1)
+					} else {
+						line = strings.Replace(line, msgs.Pause, markKey(msgs.Pause), 1)
+					}
+				}
+			}
+			// Center the line using the printable width (strip ANSI codes)
+			plainLine := stripANSI(line)
+			padding := (width - lipgloss.Width(strings.TrimSpace(plainLine))) / 2
+			if padding < 0 {
+				padding = 0
+			}
+			statusLines[i] = strings.Repeat(" ", padding) + line
+		}
+	}
+	centeredStatus := strings.Join(statusLines, "\n")
+
+	// Show the label above the donut, truncated to the donut width
+	if m.showLabel() {
+		circle = strings.Join(m.labelLines(), "\n") + "\n" + circle
+	}
+
+	// Add left padding to shift entire block left for donut and status
+	leftPadding := strings.Repeat(" ", 4)
+	output := strings.Join(strings.Split(circle, "\n"), "\n"+leftPadding) + "\n" + leftPadding + centeredStatus
+	if m.timing != nil {
+		output += "\n" + leftPadding + dimWhiteStyle.Render(m.timing.String()) // Bottom-left, out of the way
+	}
+	return circleStyle.Render(leftPadding + output)
+}
+
+// startTick starts the tick loop, unless one is already running: each tick
+// schedules the next, so a second loop would double the ticks for good.
+func (m *model) startTick() tea.Cmd {
+	if m.ticking {
+		return nil
+	}
+	m.ticking = true
+	if !m.waitUntil.IsZero() {
+		return waitTickCmd(m.waitUntil)
+	}
+	return tickCmd()
+}
+
+// tickCmd returns a Bubble Tea command that sends a tickMsg every second.
+func tickCmd() tea.Cmd {
+	return tea.Tick(tickRate, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// completeCmd schedules a completeMsg for the moment the running timer reaches
+// zero, or returns nil when nothing is counting down.
+func (m model) completeCmd() tea.Cmd {
+	if !m.isRunning || m.isPaused || m.inOvertime || m.opts.stopwatch || !m.waitUntil.IsZero() {
+		return nil
+	}
+	msg := completeMsg{startTime: m.startTime, totalTime: m.totalTime}
+	return tea.Tick(time.Until(m.startTime.Add(m.totalTime)), func(t time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// waitTickCmd returns a tick for the --at wait, at the blink cadence but
+// never later than the scheduled start.
+func waitTickCmd(until time.Time) tea.Cmd {
+	return tea.Tick(max(0, min(blinkRate, time.Until(until))), func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// tuiOptions returns the options every full-screen program is started with.
+func tuiOptions() []tea.ProgramOption {
+	return []tea.ProgramOption{tea.WithAltScreen(), tea.WithOutput(tuiOutput)}
+}
+
+// bellCmd returns a Bubble Tea command that rings the terminal bell.
+func bellCmd() tea.Cmd {
+	return func() tea.Msg {
+		fmt.Fprint(tuiOutput, "\a")
+		return nil
+	}
+}
+
+// shellCmd returns a Bubble Tea command that runs command with sh, ignoring failures.
+func shellCmd(command string) tea.Cmd {
+	return func() tea.Msg {
+		_ = exec.Command("sh", "-c", command).Run()
+		return nil
+	}
+}
+
+// pauseCheckCmd returns a Bubble Tea command that re-checks the pause started at pausedAt after a second.
+func pauseCheckCmd(pausedAt time.Time) tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return pauseCheckMsg{pausedAt: pausedAt}
+	})
+}
+
+// startBlink schedules the next blink unless one is already pending.
+func (m *model) startBlink() tea.Cmd {
+	if m.blinking {
+		return nil
+	}
+	m.blinking = true
+	if !m.isRunning && m.elapsedTime >= m.totalTime {
+		return blinkCmd(m.opts.finishedBlinkRate)
+	}
+	return blinkCmd(blinkRate)
+}
+
+// inFinale reports whether the final-minute pulse (--finale) should show.
+func (m model) inFinale() bool {
+	return m.opts.finale && m.isRunning && !m.isPaused && !m.opts.stopwatch &&
+		m.elapsedTime < m.totalTime && m.totalTime-m.elapsedTime <= finaleLength
+}
+
+// blinkCmd returns a Bubble Tea command that sends a blinkMsg after rate.
+func blinkCmd(rate time.Duration) tea.Cmd {
+	return tea.Tick(rate, func(t time.Time) tea.Msg {
+		return blinkMsg(t)
+	})
+}
+
+// ringOptions controls how drawCircle paints the ring and timer.
+type ringOptions struct {
+	timerStyle lipgloss.Style // Style for the timer text
+	paused     bool           // Dim the ring
+	secondHand float64        // Second hand position from 0 to 1 clockwise from 12 o'clock, negative for none
+	celebrate  int            // Celebration animation frame, negative for none
+	comet      bool           // Fade elapsed segments with distance behind the leading edge
+	finale     bool           // Final-minute pulse (--finale) is showing
+	marks      int            // Draw a notch at each of this many equal divisions (--minute-marks), 0 for none
+	pulse      bool           // Bright phase of the finale pulse
+	braille    bool           // Draw with Braille dots (--braille)
+	breath     float64        // Fullness of the --breathe pulse from 0 (out) to 1 (in), negative for none
+
+	finishedAnim string // --finished-anim on the full ring: "spin", "pulse" or "sparkle", "" for none
+	animFrame    int    // Frame of the finished animation
+}
+
+// cometTail is the length of the --comet fade, as a fraction of the ring.
+const cometTail = 0.3
+
+// markWidth is how far either side of a minute mark, as a fraction of the ring, is notched.
+const markWidth = 1.0 / 150
+
+// maxMinuteMarks is the most notches drawn; beyond that they would run into each other on the ring.
+const maxMinuteMarks = 15
+
+// notchChar is the glyph for --minute-marks notches.
+const notchChar = "+"
+
+// finaleEdge is how far behind the leading edge, as a fraction of the ring, pulses with --finale.
+const finaleEdge = 1.0 / 30
+
+// secondHandWidth is how far either side of the second hand position, as a fraction of the ring, is marked.
+const secondHandWidth = 1.0 / 40
+
+// cell is one character of the donut and the style it is drawn in.
+type cell struct {
+	char   string
+	style  lipgloss.Style
+	styled bool // False for plain blanks
+}
+
+// drawCircle creates a 13x29 ASCII donut with progress and timer in the center.
+// The donut fills clockwise as time elapses, and the ring is dimmed while paused.
+func drawCircle(progress float64, timer string, ring ringOptions) string {
+	var cells [][]cell
+	if ring.braille {
+		cells = brailleCells(progress, timer, ring)
+	} else {
+		cells = donutCells(progress, timer, ring)
+	}
+	lines := make([]string, len(cells))
+	for y, row := range cells {
+		line := ""
+		for _, c := range row {
+			if c.styled {
+				line += c.style.Render(c.char)
+			} else {
+				line += c.char
+			}
+		}
+		lines[y] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// donutCells lays out the donut for drawCircle, choosing the character and style of every cell.
+func donutCells(progress float64, timer string, ring ringOptions) [][]cell {
+	// ASCII donut template, 13 rows x 29 columns
+	donutTemplate := []string{
+		"          *********          ",
+		"      *****************      ",
+		"    *********************    ",
+		"  **********     **********  ",
+		" ********           ******** ",
+		" ******               ****** ",
+		" ******     mm:ss     ****** ",
+		" ******               ****** ",
+		" *******             ******* ",
+		"  **********     **********  ",
+		"    *********************    ",
+		"      *****************      ",
+		"          *********          ",
+	}
+
+	centerX, centerY := float64(width/2), float64(height/2) // Center of donut
+	totalSegments := 120                                    // Number of progress segments for smoothness
+	cells := make([][]cell, height)
+
+	// Dim the ring while paused so a halted timer is distinguishable from a running one
+	elapsedStyle, remainingStyle := whiteStyle, redStyle
+	if ring.paused {
+		elapsedStyle, remainingStyle = dimWhiteStyle, dimRedStyle
+	}
+
+	// Loop over each row of the donut
+	digits := timerCells(timer)
+	for y := 0; y < height; y++ {
+		var line []cell
+		timerStart := (width - len(digits)) / 2 // Center timer horizontally
+		timerEnd := timerStart + len(digits)
+		// Loop over each character in the row
+		for x, char := range donutTemplate[y] {
+			// With --breathe, only the outer part of the ring is drawn, deepening on each inhale
+			inner := 1 - donutDepth*ringThickness(ring.breath)
+			if char == '*' && math.Hypot((float64(x)-centerX)/(width/2), (float64(y)-centerY)/(height/2)) >= inner {
+				// Calculate angle for progress marker (0 at the start angle, clockwise)
+				angle := ringPosition(float64(x)-centerX, float64(y)-centerY) * 2 * math.Pi
+				segment := int((angle / (2 * math.Pi)) * float64(totalSegments))
+				// Second hand sweeps over the fill; otherwise white for elapsed, red for remaining
+				if ring.celebrate >= 0 {
+					// Bands of color that shift one step per frame
+					line = append(line, cell{ringChar, celebrateStyles[(segment/10+ring.celebrate)%len(celebrateStyles)], true})
+				} else if ring.animLit(angle / (2 * math.Pi)) {
+					line = append(line, cell{ringChar, highlightStyle, true})
+				} else if ring.secondHand >= 0 && ringDistance(angle/(2*math.Pi), ring.secondHand) < secondHandWidth {
+					line = append(line, cell{ringChar, handStyle, true})
+				} else if ring.marks > 0 && isMark(angle/(2*math.Pi), ring.marks) {
+					// Notch in the fill's own color so progress still reads through it
+					style := remainingStyle
+					if segment < int(progress*float64(totalSegments)) {
+						style = elapsedStyle
+					}
+					line = append(line, cell{notchChar, style, true})
+				} else if segment < int(progress*float64(totalSegments)) {
+					if ring.finale && ring.pulse && progress-angle/(2*math.Pi) < finaleEdge {
+						line = append(line, cell{ringChar, highlightStyle, true}) // Pulsing leading edge
+					} else if ring.comet && !ring.paused {
+						// Brightest at the leading edge, fading to dim over the tail
+						behind := progress - angle/(2*math.Pi)
+						level := int((1 - math.Min(behind/cometTail, 1)) * float64(len(cometStyles)-1))
+						line = append(line, cell{ringChar, cometStyles[level], true})
+					} else {
+						line = append(line, cell{ringChar, elapsedStyle, true})
+					}
+				} else if ring.finale && ring.pulse {
+					line = append(line, cell{ringChar, finaleStyle, true}) // Remaining arc flashes bright
+				} else {
+					line = append(line, cell{ringChar, remainingStyle, true})
+				}
+			} else if y == height/2 && x >= timerStart && x < timerEnd {
+				// Place the actual timer in the center row
+				line = append(line, cell{digits[x-timerStart], ring.timerStyle, true})
+			} else {
+				line = append(line, cell{char: " "})
+			}
+		}
+		cells[y] = line
+	}
+
+	return cells
+}
+
+// truncateText shortens s to at most n runes, ending with an ellipsis when cut.
+func truncateText(s string, n int) string {
+	if n < 1 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// maxLabelLines caps how many lines --wrap-label may use.
+const maxLabelLines = 2
+
+// labelLines returns the label lines shown above the donut, centered: one
+// truncated line, or with --wrap-label up to maxLabelLines wrapped ones.
+func (m model) labelLines() []string {
+	badge := m.categoryBadge()
+	if !m.opts.wrapLabel {
+		if badge == "" {
+			return []string{centerText(truncateText(m.label, width))}
+		}
+		if m.label == "" {
+			return []string{centerText(truncateText(badge, width))}
+		}
+		return []string{centerText(truncateText(m.label, width-len([]rune(badge))-1) + " " + badge)}
+	}
+	lines := wrapText(strings.TrimSpace(m.label+" "+badge), width)
+	if len(lines) == 0 {
+		return []string{""} // Keep the line for an unlabeled step of a plan
+	}
+	if len(lines) > maxLabelLines {
+		// Cut the last line kept to make room for an ellipsis
+		last := []rune(lines[maxLabelLines-1])
+		for len(last) > 0 && lipgloss.Width(string(last))+1 > width {
+			last = last[:len(last)-1]
+		}
+		lines = lines[:maxLabelLines]
+		lines[maxLabelLines-1] = string(last) + "…"
+	}
+	for i, line := range lines {
+		lines[i] = strings.Repeat(" ", max(0, (width-lipgloss.Width(line))/2)) + line
+	}
+	return lines
+}
+
+// centerText pads plain text on the left so it sits centered within the donut width.
+func centerText(s string) string {
+	padding := (width - len([]rune(s))) / 2
+	if padding < 0 {
+		padding = 0
+	}
+	return strings.Repeat(" ", padding) + s
+}
+
+// isMark reports whether ring position pos (0 to 1) lies on one of the n-1
+// boundaries dividing the ring into n equal parts.
+func isMark(pos float64, n int) bool {
+	k := math.Round(pos * float64(n))
+	if k <= 0 || k >= float64(n) {
+		return false // The start of the ring is not a boundary
+	}
+	return math.Abs(pos-k/float64(n)) < markWidth
+}
+
+// ringPosition returns where the point at offset (dx, dy) from the center lies
+// around the ring, from 0 at the start angle to 1, clockwise.
+func ringPosition(dx, dy float64) float64 {
+	angle := math.Atan2(dy, dx) + math.Pi/2 - startAngle // 0 at 12 o'clock, then offset
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle / (2 * math.Pi)
+}
+
+// ringDistance returns the distance between two ring positions in [0, 1), going the short way around.
+func ringDistance(a, b float64) float64 {
+	d := math.Abs(a - b)
+	return math.Min(d, 1-d)
+}
+
+// stripANSI removes ANSI escape codes for accurate width calculation when centering highlighted text.
+// OSC sequences such as hyperlinks (ESC ] ... BEL or ESC ] ... ESC \) are removed whole.
+func stripANSI(str string) string {
+	const (
+		text = iota
+		escape
+		csi // ESC [ ... up to a final letter
+		osc // ESC ] ... up to BEL or ESC \
+	)
+	state := text
+	out := make([]rune, 0, len(str))
+	for _, r := range str {
+		switch state {
+		case text:
+			if r == 27 { // ESC
+				state = escape
+				continue
+			}
+			out = append(out, r)
+		case escape:
+			switch r {
+			case ']':
+				state = osc
+			case '\\':
+				state = text // String terminator ending an OSC
+			default:
+				state = csi
+				if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+					state = text
+				}
+			}
+		case csi:
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				state = text
+			}
+		case osc:
+			if r == 7 { // BEL
+				state = text
+			} else if r == 27 {
+				state = escape
+			}
+		}
+	}
+	return string(out)
+}
+
+// hyperlink wraps text in an OSC 8 escape so terminals that support it make it clickable.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// updateStreak recounts the --streak days from the history file, which now
+// includes the session just finished.
+func (m *model) updateStreak() {
+	if !m.opts.streak || m.opts.historyPath == "" {
+		return
+	}
+	if entries, err := readHistory(m.opts.historyPath); err == nil {
+		m.streak = focusStreak(entries, time.Now())
+	}
+}
+
+// streakLines returns the --streak line for the finished screen, if there is a streak.
+func (m model) streakLines() []string {
+	if m.streak < 1 {
+		return nil
+	}
+	return []string{fmt.Sprintf("🔥 %d-day streak", m.streak)}
+}
+
+// linkLines returns the --link line for the finished screen: the label, or the
+// URL itself when there is no label, made clickable with an OSC 8 hyperlink.
+func (m model) linkLines() []string {
+	if m.opts.link == "" {
+		return nil
+	}
+	text := m.label
+	if text == "" {
+		text = m.opts.link
+	}
+	return []string{hyperlink(m.opts.link, "🔗 "+truncateText(text, width-3))}
+}
+
+// main is the entry point. It parses arguments, initializes the model, and runs the Bubble Tea program.
+func main() {
+	// Control a running instance: gopomotime ctl pause|resume|reset|status
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(runCtl(os.Args[2:]))
+	}
+
+	// Maintain the history file: gopomotime history clear|rotate
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		os.Exit(runHistory(os.Args[2:]))
+	}
+
+	// Parse flags
+	var opts options
+	flag.BoolVar(&opts.overtime, "overtime", false, "count overtime upward after the timer finishes")
+	flag.StringVar(&opts.historyPath, "history", "", "append each session to this JSONL `file`")
+	flag.StringVar(&opts.orgLogPath, "org-log", "", "append a CLOCK entry for each completed session to this org-mode `file`")
+	flag.DurationVar(&opts.splitPause, "split-pause", 0, "log a session as separate history entries around any pause longer than this `duration` (e.g. 30m)")
+	flag.DurationVar(&opts.roundLog, "round-log", 0, "round durations written to the history to this `increment` (e.g. 1m); the display stays exact")
+	flag.DurationVar(&opts.minLog, "min-log", 0, "don't log unfinished sessions shorter than this `duration` (e.g. 30s)")
+	mute := flag.Bool("mute", false, "start with the completion bell muted (toggle with m)")
+	presetName := flag.String("preset", "", "start the timer defined as preset-`name` in config.toml")
+	planPath := flag.String("plan", "", "run the \"mm:ss label\" timers listed in `file` in sequence")
+	tasksPath := flag.String("tasks", "", "run the timers in a JSON `file` of {\"duration\", \"label\", \"category\"} objects in sequence")
+	interval := flag.String("interval", "", "alternate work and rest phases given as `work/rest` (e.g. 25:00/5:00)")
+	work := flag.String("work", "", "run pomodoros with work phases of `mm:ss`, or a random length in a range like 20:00-30:00")
+	breakSpec := flag.String("break", "5:00", "break length for --work, as `mm:ss` or a range like 3:00-7:00")
+	cooldown := flag.String("cooldown", "", "with --interval or --work, end with a wind-down phase of `mm:ss`")
+	rounds := flag.Int("rounds", 4, "number of work rounds for --interval or --work")
+	longBreak := flag.String("long-break", "", "with --interval or --work, make every --long-break-every'th rest a long break of `mm:ss`")
+	longBreakEvery := flag.Int("long-break-every", 4, "take the --long-break after this many work `rounds`")
+	seedFlag := flag.Int64("seed", 0, "seed for random choices (--work ranges, quotes); 0 picks one from the clock")
+	flag.StringVar(&opts.scheduleDaily, "schedule-daily", "", "with --plan, run the plan every day at the local `time` hh:mm, waiting in between")
+	at := flag.String("at", "", "wait until the local `time` hh:mm (today, or tomorrow if it has passed) before starting")
+	controlFD := flag.Int("control-fd", -1, "also read ctl commands, one per line, from this open file `descriptor` (e.g. 3) passed by a parent process")
+	metricsAddr := flag.String("metrics", "", "serve Prometheus metrics on `addr` (e.g. :9090) at /metrics")
+	flag.DurationVar(&opts.pauseTimeout, "pause-timeout", 0, "after being paused this `long` (e.g. 5m), resume or stop automatically")
+	flag.StringVar(&opts.pauseTimeoutAction, "pause-timeout-action", "stop", "what --pause-timeout does: `resume` or stop")
+	quotesPath := flag.String("quotes", "", "show a random line from `file` when the timer finishes")
+	flag.BoolVar(&opts.secondHand, "second-hand", false, "sweep a marker around the ring once per second")
+	flag.StringVar(&opts.onComplete, "on-complete", "wait", "after the last timer: `wait`, quit, celebrate or loop")
+	flag.BoolVar(&opts.comet, "comet", false, "fade elapsed segments behind the leading edge like a comet tail")
+	flag.StringVar(&opts.snapshotPath, "snapshot", "", "on completion, save the finished donut and stats to this SVG `file`")
+	flag.BoolVar(&opts.stopwatch, "stopwatch", false, "count up from zero instead of down")
+	flag.BoolVar(&opts.precise, "precise", false, "show hundredths of a second (ss.cc) in --stopwatch mode")
+	flag.BoolVar(&opts.strict, "strict", false, "disable [q]uit and [r]eset while a timer runs; Ctrl-C still quits")
+	angle := flag.Int("start-angle", 0, "`degrees` clockwise from 12 o'clock where the fill begins (0-359)")
+	char := flag.String("char", "*", "single-width `glyph` used to draw the ring (e.g. # or ●)")
+	flag.BoolVar(&opts.score, "score", false, "rate each session from 0 to 100 on the --summary screen and in the history")
+	flag.IntVar(&opts.pausePenalty, "score-pause-penalty", 10, "`points` off the --score for each pause")
+	flag.IntVar(&opts.incompletePenalty, "score-incomplete-penalty", 30, "`points` off the --score for stopping before the end")
+	flag.BoolVar(&opts.persistSummary, "persist-summary", false, "on finishing, leave the full screen and keep the finished donut and a summary in the scrollback")
+	flag.BoolVar(&opts.summary, "summary", false, "show a session summary when quitting with q")
+	useTmux := flag.Bool("tmux", false, "mirror the timer into the tmux option @gopomotime for status-right")
+	titleFormat := flag.String("title-format", "", "write the timer into the terminal title using a `template` with {remaining}, {percent} and {label}")
+	exitFormat := flag.String("exit-message", defaultExitMessage, "line printed after the timer closes, a `template` with {elapsed}, {label}, {completed} and {score}")
+	noExitMessage := flag.Bool("no-exit-message", false, "don't print a line after the timer closes")
+	last := flag.Bool("last", false, "repeat the most recent timer from --history, with the same duration and label")
+	recordPath := flag.String("record", "", "write a rendered frame to `file` about once a second, for replaying or making demo GIFs")
+	raise := flag.Bool("raise", false, "bring the terminal window to the front on start (wmctrl/xdotool or AppleScript)")
+	attach := flag.Bool("attach", false, "show the timer of the gopomotime already running, read-only, instead of starting one")
+	onExisting := flag.String("on-existing", "error", "what to do when gopomotime is already running: `error`, attach to it, or replace it")
+	grid := flag.Bool("grid", false, "run each `mm:ss[=label]` argument as an independent timer, side by side")
+	tuiStream := flag.String("tui-stream", "stdout", "draw the timer on `stdout` or stderr")
+	theme := flag.String("theme", "auto", "color `theme`: dark, light, colorblind, or auto to match the terminal background")
+	flag.StringVar(&opts.workEndSound, "work-end-sound", "", "shell `command` to play when a work phase ends, instead of the bell")
+	flag.StringVar(&opts.breakEndSound, "break-end-sound", "", "shell `command` to play when a break ends, instead of the bell")
+	flag.StringVar(&opts.workEndMessage, "work-end-message", "", "status `text` to show when a work phase ends")
+	flag.StringVar(&opts.breakEndMessage, "break-end-message", "", "status `text` to show when a break ends")
+	useSyslog := flag.Bool("syslog", false, "record each finished timer in the system log (journald on systemd)")
+	flag.StringVar(&opts.exec, "exec", "", "shell `command` to run whenever a timer finishes, with $GOPOMO_LABEL and $GOPOMO_DURATION set")
+	flag.StringVar(&opts.link, "link", "", "show a clickable link to this `URL` (e.g. the task) on the finished screen")
+	flag.BoolVar(&opts.wrapLabel, "wrap-label", false, "wrap a long label onto two lines above the donut instead of truncating it")
+	flag.StringVar(&opts.transitionSound, "transition-sound", "all", "which phase ends sound before the last timer: `all`, work-only, break-only or none")
+	flag.BoolVar(&opts.speak, "speak", false, "announce the --speak-at milestones and the finish aloud (say, spd-say or espeak)")
+	speakAt := flag.String("speak-at", "5m,1m", "comma-separated `times` remaining to announce with --speak")
+	flag.IntVar(&maxMinutes, "max-minutes", maxMinutes, "largest `minutes` accepted in mm:ss durations, up to 999 (e.g. 180 to allow 120:00)")
+	flag.IntVar(&opts.repeatSound, "repeat-sound", 1, "ring the completion bell this many `times`, 300ms apart")
+	finishedColor := flag.String("finished-color", "", "`#RRGGBB` color of the finished message, instead of the theme's green")
+	flag.DurationVar(&opts.finishedBlinkRate, "finished-blink-rate", blinkRate, "how fast the finished screen blinks, from 100ms to 5s")
+	flag.StringVar(&opts.finishedAnim, "finished-anim", "none", "animate the ring when finished: `spin`, pulse, sparkle or none")
+	flag.BoolVar(&opts.clockThenDonut, "clock-then-donut", false, "start stopped with the time in big digits, switching to the donut once p starts it")
+	flag.BoolVar(&opts.breathe, "breathe", false, "guide breathing: the ring swells and thins on --breathe-cycle with an Inhale/Exhale cue")
+	breatheCycle := flag.String("breathe-cycle", "4s,4s", "`inhale,exhale` times for --breathe")
+	flag.BoolVar(&opts.softFinish, "soft-finish", false, "build the completion bell up gently: one ring, then two, then three, 2s apart")
+	icons := flag.Bool("icons", false, "show the controls as icons (⏹ q ↺ r ⏸ p) instead of words")
+	localDigitsFlag := flag.Bool("local-digits", false, "draw the timer and percentage in the digits of the locale from LANG, e.g. ٠١٢ for Arabic")
+	flag.BoolVar(&opts.resetClearsLabel, "reset-clears-label", false, "clear the label when resetting with r (by default the label is kept)")
+	flag.BoolVar(&opts.hideControls, "hide-controls", false, "leave out the [q]uit [r]eset [p]ause line, e.g. for recordings (keys still work)")
+	categories := flag.String("categories", "", "comma-separated category `names` to tag a session with, picked with the keys 1-9")
+	flag.IntVar(&opts.dailyGoal, "daily-goal", 0, "celebrate with a fanfare and sparkles when today's completed sessions reach `n` (needs --history)")
+	flag.IntVar(&opts.trendDays, "trend", 0, "show a sparkline of the focus time logged on each of the last `days` days (needs --history)")
+	flag.BoolVar(&opts.streak, "streak", false, "show how many days in a row you have completed a session (needs --history)")
+	flag.BoolVar(&opts.roundDisplay, "round-display", false, "round the displayed time to the nearest second instead of truncating")
+	flag.DurationVar(&opts.idlePause, "idle-pause", 0, "pause after this `long` (e.g. 2m) without keyboard or mouse input, resuming on input (X11, needs xprintidle)")
+	flag.BoolVar(&opts.braille, "braille", false, "draw a smoother ring with Braille dots (needs a font with Braille patterns)")
+	flag.BoolVar(&opts.minuteMarks, "minute-marks", false, "notch the ring at each whole minute (for timers up to 15 minutes)")
+	showElapsed := flag.Bool("show-elapsed", false, "show elapsed instead of remaining time in the donut (toggle with t)")
+	flag.BoolVar(&opts.finale, "finale", false, "pulse the leading edge and blink the remaining arc in the final minute")
+	debugTiming := flag.Bool("debug-timing", false, "show the min/avg/max jitter of the screen ticks, to diagnose a stuttering donut (e.g. over SSH)")
+	flag.BoolVar(&opts.debug, "debug", false, "enable testing keys: J jumps to 10 seconds remaining")
+	noAutoTheme := flag.Bool("no-auto-theme", false, "keep full-brightness colors in the evening")
+	strictConfig := flag.Bool("strict-config", false, "exit on an error in config.toml instead of ignoring the file")
+	showVersion := flag.Bool("version", false, "print version and build information, then exit")
+	flag.Usage = func() {
+		fmt.Println("Usage: gopomotime [flags] mm:ss")
+		fmt.Println("       gopomotime [flags] --plan file")
+		fmt.Println("       gopomotime [flags] --plan file --schedule-daily hh:mm")
+		fmt.Println("       gopomotime [flags] --tasks file.json")
+		fmt.Println("       gopomotime [flags] --interval work/rest [--rounds n]")
+		fmt.Println("       gopomotime [flags] --work mm:ss[-mm:ss] [--break mm:ss[-mm:ss]] [--rounds n]")
+		fmt.Println("       gopomotime [flags] --grid mm:ss[=label] ...")
+		fmt.Println("       gopomotime [flags] --stopwatch [--precise]")
+		fmt.Println("       gopomotime [flags] --history file --last")
+		fmt.Println("       gopomotime ctl pause|resume|reset|status")
+		fmt.Println("       gopomotime history clear|rotate [--history file] [--yes]")
+		fmt.Println("       gopomotime history import file.csv [--history file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Print build information before anything else
+	if *showVersion {
+		fmt.Printf("gopomotime %s (commit %s, built %s)\n", version, commit, date)
+		return
+	}
+
+	// Fill in defaults from config.toml. A broken file is reported and skipped
+	// so it never stops the timer from starting, unless --strict-config is set.
+	if path, source := configPath(); path != "" {
+		if err := applyConfig(path, source == "$"+configEnv); err != nil {
+			if *strictConfig {
+				fmt.Printf("Error: %v (config file from %s)\n", err, source)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v (config file from %s; ignoring it, using defaults)\n", err, source)
+		}
+	}
+
+	if maxMinutes < 1 || maxMinutes > maxMinutesLimit {
+		fmt.Printf("Error: --max-minutes must be between 1 and %d\n", maxMinutesLimit)
+		os.Exit(1)
+	}
+
+	if opts.dailyGoal < 0 {
+		fmt.Println("Error: --daily-goal can't be negative")
+		os.Exit(1)
+	}
+	if opts.dailyGoal > 0 && opts.historyPath == "" {
+		fmt.Println("Error: --daily-goal needs a history file (--history)")
+		os.Exit(1)
+	}
+
+	if opts.trendDays < 0 || opts.trendDays > maxTrendDays {
+		fmt.Printf("Error: --trend must be between 1 and %d days\n", maxTrendDays)
+		os.Exit(1)
+	}
+
+	if *categories != "" {
+		list, err := parseCategories(*categories)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		opts.categories = list
+	}
+
+	// Check for correct argument count
+	modes := 0
+	for _, on := range []bool{*planPath != "", *tasksPath != "", *interval != "", *work != "", opts.stopwatch, *grid, *last, *attach, *presetName != ""} {
+		if on {
+			modes++
+		}
+	}
+	noDuration := modes > 0 && !*grid // Modes that take no duration argument
+	if modes > 1 || (noDuration && flag.NArg() != 0) || (modes == 0 && flag.NArg() > 1) || (opts.precise && !opts.stopwatch) {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if opts.scheduleDaily != "" {
+		if *planPath == "" || *at != "" || opts.onComplete != "wait" {
+			fmt.Println("Error: --schedule-daily needs --plan, and can't be combined with --at or --on-complete")
+			os.Exit(1)
+		}
+		if _, err := parseClock(opts.scheduleDaily, time.Now()); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	if opts.idlePause > 0 && !idleAvailable() {
+		fmt.Fprintln(os.Stderr, "Warning: --idle-pause needs an X11 display and xprintidle; carrying on without it")
+		opts.idlePause = 0
+	}
+
+	if opts.pauseTimeoutAction != "resume" && opts.pauseTimeoutAction != "stop" {
+		fmt.Println("Error: --pause-timeout-action must be resume or stop")
+		os.Exit(1)
+	}
+
+	switch opts.onComplete {
+	case "wait", "quit", "celebrate", "loop":
+	default:
+		fmt.Println("Error: --on-complete must be wait, quit, celebrate or loop")
+		os.Exit(1)
+	}
+
+	if *cooldown != "" && *interval == "" && *work == "" {
+		fmt.Println("Error: --cooldown needs --interval or --work")
+		os.Exit(1)
+	}
+	if *longBreak != "" && ((*interval == "" && *work == "") || *planPath != "" || *tasksPath != "") {
+		fmt.Println("Error: --long-break needs --interval or --work, and can't be combined with --plan or --tasks")
+		os.Exit(1)
+	}
+	if *longBreakEvery < 1 {
+		fmt.Println("Error: --long-break-every must be at least 1")
+		os.Exit(1)
+	}
+
+	if opts.pausePenalty < 0 || opts.incompletePenalty < 0 {
+		fmt.Println("Error: --score penalties can't be negative")
+		os.Exit(1)
+	}
+
+	switch opts.transitionSound {
+	case "all", "work-only", "break-only", "none":
+	default:
+		fmt.Println("Error: --transition-sound must be all, work-only, break-only or none")
+		os.Exit(1)
+	}
+
+	if opts.speak {
+		milestones, err := parseMilestones(*speakAt)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		opts.speakAt = milestones
+	}
+
+	if opts.repeatSound < 1 || opts.repeatSound > 10 {
+		fmt.Println("Error: --repeat-sound must be between 1 and 10")
+		os.Exit(1)
+	}
+	if opts.finishedBlinkRate < minFinishedBlinkRate || opts.finishedBlinkRate > maxFinishedBlinkRate {
+		fmt.Println("Error: --finished-blink-rate must be between 100ms and 5s")
+		os.Exit(1)
+	}
+	if *finishedColor != "" && !isHexColor(*finishedColor) {
+		fmt.Println("Error: --finished-color must be a #RRGGBB color, e.g. #00BFFF")
+		os.Exit(1)
+	}
+
+	switch opts.finishedAnim {
+	case "spin", "pulse", "sparkle", "none":
+	default:
+		fmt.Println("Error: --finished-anim must be spin, pulse, sparkle or none")
+		os.Exit(1)
+	}
+
+	switch *onExisting {
+	case "error", "attach", "replace":
+	default:
+		fmt.Println("Error: --on-existing must be error, attach or replace")
+		os.Exit(1)
+	}
+
+	if opts.breathe {
+		cycle, err := parseBreathCycle(*breatheCycle)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		opts.breathCycle = cycle
+	}
+
+	if opts.softFinish && opts.repeatSound != 1 {
+		fmt.Println("Error: --soft-finish sets its own rings and can't be combined with --repeat-sound")
+		os.Exit(1)
+	}
+
+	if utf8.RuneCountInString(*char) != 1 || lipgloss.Width(*char) != 1 {
+		fmt.Println("Error: --char must be a single character one column wide")
+		os.Exit(1)
+	}
+	ringChar = *char
+
+	if *angle < 0 || *angle > 359 {
+		fmt.Println("Error: --start-angle must be between 0 and 359")
+		os.Exit(1)
+	}
+	startAngle = float64(*angle) * math.Pi / 180
+
+	if err := validateTitleFormat(*titleFormat); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if err := validateExitMessage(*exitFormat); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	// Draw on stderr if asked, so stdout stays free for piping
+	switch *tuiStream {
+	case "stdout":
+	case "stderr":
+		tuiOutput.File = os.Stderr
+		lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(os.Stderr)) // Detect colors on the terminal actually drawn to
+	default:
+		fmt.Println("Error: --tui-stream must be stdout or stderr")
+		os.Exit(1)
+	}
+
+	// Pick the color palette once at startup
+	themeName, err := resolveTheme(*theme)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	// Dim the colors in the evening, unless a theme was chosen explicitly
+	pal := palettes[themeName]
+	themeSet := false
+	flag.Visit(func(f *flag.Flag) { themeSet = themeSet || f.Name == "theme" })
+	if !themeSet && !*noAutoTheme && isNight(time.Now().Hour()) {
+		pal = nightPalette(pal)
+	}
+	if *finishedColor != "" {
+		pal.finished = lipgloss.Color(*finishedColor) // Kept as given, even in the evening
+	}
+	applyPalette(pal)
+	monochrome = detectMonochrome()
+
+	// Pick the on-screen language from the environment
+	msgs, err = loadMessages(localeFromEnv())
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if *icons {
+		msgs = msgs.withIcons()
+	}
+	if *localDigitsFlag {
+		useLocalDigits(localeFromEnv())
+	}
+
+	// Show another instance's timer instead of starting one
+	if *attach {
+		runAttach()
+		return
+	}
+
+	// Grid mode runs its own program with independent timers
+	if *grid {
+		if flag.NArg() == 0 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		timers, err := parseGridArgs(flag.Args(), time.Now())
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if _, err := tea.NewProgram(gridModel{timers: timers, muted: *mute, ticking: true}, tuiOptions()...).Run(); err != nil {
+			fmt.Println("Error running program:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// One random source for the whole run, fixed by --seed for repeatable runs
+	seed := *seedFlag
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// Presets come from config.toml, for --preset and the P key
+	presets := loadPresets()
+
+	// Load the plan file, build the intervals, ask for a duration, or parse the duration argument as a single timer
+	var plan []segment
+	if *planPath != "" {
+		plan, err = loadPlan(*planPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	} else if *tasksPath != "" {
+		plan, err = loadTasks(*tasksPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	} else if *interval != "" {
+		plan, err = intervalPlan(*interval, *rounds, rng)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	} else if *work != "" {
+		plan, err = pomodoroPlan(*work, *breakSpec, *rounds, rng)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	} else if opts.stopwatch {
+		plan = []segment{{duration: stopwatchDuration}}
+	} else if *presetName != "" {
+		p, err := findPreset(presets, *presetName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		plan = []segment{{duration: p.duration, label: p.label}}
+	} else if *last {
+		if opts.historyPath == "" {
+			fmt.Println("Error: --last needs a history file (--history)")
+			os.Exit(1)
+		}
+		entry, err := lastTimer(opts.historyPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		plan = []segment{{duration: time.Duration(entry.PlannedSeconds) * time.Second, label: entry.Label}}
+	} else if flag.NArg() == 0 {
+		duration, ok, err := promptDuration()
+		if err != nil {
+			fmt.Println("Error running program:", err)
+			os.Exit(1)
+		}
+		if !ok {
+			return // Cancelled at the prompt
+		}
+		plan = []segment{{duration: duration}}
+	} else {
+		duration, err := parseDuration(flag.Arg(0))
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		plan = []segment{{duration: duration}}
+	}
+
+	// Stretch every few rests into a long break
+	if *longBreak != "" {
+		duration, err := parseDuration(*longBreak)
+		if err != nil {
+			fmt.Println("Error: long break:", err)
+			os.Exit(1)
+		}
+		if *rounds < *longBreakEvery {
+			fmt.Fprintf(os.Stderr, "Warning: --long-break: only %d rounds, so none of the rests is a long break\n", *rounds)
+		}
+		plan = addLongBreaks(plan, duration, *longBreakEvery)
+	}
+
+	// Wind down after the last work phase
+	if *cooldown != "" {
+		duration, err := parseDuration(*cooldown)
+		if err != nil {
+			fmt.Println("Error: cooldown:", err)
+			os.Exit(1)
+		}
+		plan = append(plan, segment{duration: duration, label: "Cooldown", kind: phaseCooldown})
+	}
+
+	// Initialize the model with the first timer
+	now := time.Now()
+	m := model{
+		opts:         opts,
+		plan:         plan,
+		label:        plan[0].label,
+		category:     plan[0].category,
+		totalTime:    plan[0].duration,
+		elapsedTime:  0,
+		isRunning:    true, // Start timer immediately
+		isPaused:     false,
+		blink:        true, // Start with text visible
+		blinking:     true, // Init starts the blink loop
+		ticking:      true, // and the tick loop
+		startTime:    now,  // For smooth progress
+		sessionStart: now,
+		muted:        *mute,
+		presets:      presets,
+		showElapsed:  *showElapsed,
+	}
+	if opts.clockThenDonut {
+		m.isRunning = false // Shown in big digits until p starts it
+	}
+	if *at != "" {
+		start, err := parseClock(*at, now)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		m.isRunning = false
+		m.waitUntil = start
+	}
+	if opts.scheduleDaily != "" {
+		m.waitForNextRun()
+	}
+	m.queueCommands() // Hold the first timer for its plan commands, unless it waits to start
+
+	// Load quotes for the finished screen
+	m.rng = rng
+	if *quotesPath != "" {
+		m.quotes, err = loadQuotes(*quotesPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	// Serve metrics for the lifetime of the program
+	if *metricsAddr != "" {
+		m.metrics = &metrics{}
+		m.publishMetrics()
+		shutdown, err := serveMetrics(*metricsAddr, m.metrics)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer shutdown()
+	}
+
+	// Mirror the timer into tmux, clearing it when the program exits; outside tmux, warn and carry on
+	if *useTmux && os.Getenv("TMUX") == "" {
+		fmt.Fprintln(os.Stderr, "Warning: --tmux: not running inside tmux")
+	} else if *useTmux {
+		m.tmux = newTmuxStatus()
+		m.tmux.set(m.timerText())
+		defer m.tmux.clear()
+	}
+
+	if *debugTiming {
+		m.timing = &tickTiming{}
+	}
+
+	// Keep the terminal title in step with the timer, blanking it on exit
+	if *titleFormat != "" {
+		m.title = &windowTitle{format: *titleFormat}
+		defer m.title.clear()
+	}
+
+	// Save frames for replaying, closing the file when the program exits
+	if *recordPath != "" {
+		m.recorder, err = newFrameRecorder(*recordPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer m.recorder.close()
+	}
+
+	// Log finished timers to the system logger; without one, warn and carry on
+	if *useSyslog {
+		if m.sysLog, err = openSystemLog(); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: --syslog:", err)
+		} else {
+			defer m.sysLog.close()
+		}
+	}
+
+	// Check the control descriptor before taking over the screen
+	var controlFile *os.File
+	if *controlFD >= 0 {
+		controlFile, err = openControlFD(*controlFD)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	// Only one timer runs at a time; --on-existing decides what a second launch
+	// does. A parent passing --control-fd manages its own instances.
+	if controlFile == nil {
+		release, err := acquireLock(lockPath())
+		if running, ok := err.(errRunning); ok {
+			switch *onExisting {
+			case "attach":
+				runAttach()
+				return
+			case "replace":
+				release, err = replaceRunning(lockPath(), running.pid)
+			}
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer release()
+	}
+
+	if *raise {
+		raiseWindow()
+	}
+
+	// Start the Bubble Tea program with alternate screen
+	p := tea.NewProgram(m, tuiOptions()...)
+
+	// Accept `gopomotime ctl` commands; only the first running instance can be
+	// controlled. A parent passing --control-fd gets no socket file.
+	if controlFile != nil {
+		go serveControlFD(controlFile, p)
+	} else if ln, err := listenControl(controlSocketPath()); err == nil {
+		go serveControl(ln, p)
+		defer ln.Close() // Also removes the socket file
+	}
+
+	final, err := p.Run()
+	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
+
+	// Leave a line in the scrollback about the session just closed
+	if !*noExitMessage {
+		if text := exitMessage(*exitFormat, final.(model)); text != "" {
+			fmt.Println(text)
+		}
+	}
+}
+
+// runAttach shows the running instance's timer read-only, for --attach and
+// --on-existing attach, exiting if there is none.
+func runAttach() {
+	if _, err := queryControl(stateCommand); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	final, err := tea.NewProgram(attachModel{}, tuiOptions()...).Run()
+	if err != nil {
+		fmt.Println("Error running program:", err)
+		os.Exit(1)
+	}
+	if err := final.(attachModel).err; err != nil {
+		fmt.Println("Detached:", err)
+	}
 }
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestMain renders without color, as on a monochrome terminal, so frames are
+// plain text and the same on every machine.
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	monochrome = true
+	os.Exit(m.Run())
+}
+
+// testEpoch is the fixed instant frames and timers are built around.
+var testEpoch = time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+// newTestModel returns a running timer of length total, started at testEpoch
+// with elapsed already counted, and the flag defaults View depends on.
+func newTestModel(total, elapsed time.Duration) model {
+	return model{
+		opts:         options{finishedAnim: "none", repeatSound: 1},
+		totalTime:    total,
+		elapsedTime:  elapsed,
+		isRunning:    true,
+		blink:        true,
+		startTime:    testEpoch,
+		sessionStart: testEpoch,
+		clock:        func() time.Time { return testEpoch.Add(elapsed) },
+	}
+}
+
+// checkGolden compares got with testdata/name, rewriting it under -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: frame differs from the golden file\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// pressP presses p and lets the highlight lapse, which applies the toggle.
+func pressP(t *testing.T, m model) model {
+	t.Helper()
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	next, _ = next.(model).Update(highlightMsg{})
+	return next.(model)
+}
+
+func TestViewGolden(t *testing.T) {
+	running := newTestModel(25*time.Minute, 10*time.Minute)
+
+	paused := running
+	paused.isPaused = true
+	paused.pausedAt = testEpoch.Add(10 * time.Minute)
+	paused.highlightKey = "p" // Just pressed, so un[p]ause is marked
+	paused.highlightUntil = paused.pausedAt.Add(highlightDuration)
+
+	finished := newTestModel(25*time.Minute, 25*time.Minute)
+	finished.isRunning = false
+	finished.finishedAt = testEpoch.Add(25 * time.Minute)
+
+	blinkOff := finished
+	blinkOff.blink = false
+
+	tests := []struct {
+		name string
+		m    model
+	}{
+		{"running.golden", running},
+		{"paused.golden", paused},
+		{"finished_blink_on.golden", finished},
+		{"finished_blink_off.golden", blinkOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGolden(t, tt.name, tt.m.View())
+		})
+	}
+}
+
+func TestCompleteAfterPauseIsRescheduled(t *testing.T) {
+	const total = 300 * time.Millisecond
+	m := newTestModel(total, 0)
+	m.clock = nil
+	m.plan = []segment{{duration: total}}
+	m.startTime = time.Now().Add(-100 * time.Millisecond)
+	stale := completeMsg{startTime: m.startTime, totalTime: m.totalTime}
+
+	m = pressP(t, m) // Pause
+	time.Sleep(50 * time.Millisecond)
+	m = pressP(t, m) // Resume, 50ms later
+
+	// The end scheduled before the pause has passed its time and must not complete the timer
+	next, _ := m.Update(stale)
+	m = next.(model)
+	if !m.isRunning {
+		t.Fatal("stale completeMsg from before the pause completed the timer")
+	}
+
+	msg := m.completeCmd()()
+	if got := time.Now(); got.Before(m.startTime.Add(total)) {
+		t.Errorf("completeMsg fired %v before startTime+totalTime", m.startTime.Add(total).Sub(got))
+	}
+	if cm, ok := msg.(completeMsg); !ok || !cm.startTime.Equal(m.startTime) {
+		t.Fatalf("completeCmd sent %#v, want a completeMsg for the resumed start time", msg)
+	}
+	next, _ = m.Update(msg)
+	if next.(model).isRunning {
+		t.Error("the rescheduled completeMsg did not complete the timer")
+	}
+}
+
+// countTicks runs cmd and any batch it returns, and counts the tickMsgs sent
+// within wait. Commands still sleeping after wait (completeCmd) are left behind.
+func countTicks(cmd tea.Cmd, wait time.Duration) int {
+	if cmd == nil {
+		return 0
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		switch msg := msg.(type) {
+		case tickMsg:
+			return 1
+		case tea.BatchMsg:
+			n := 0
+			for _, c := range msg {
+				n += countTicks(c, wait)
+			}
+			return n
+		}
+	case <-time.After(wait):
+	}
+	return 0
+}
+
+func TestCompleteKeepsOneTickLoop(t *testing.T) {
+	m := newTestModel(time.Minute, time.Minute)
+	m.clock = nil
+	m.muted = true
+	m.plan = []segment{{duration: time.Minute}, {duration: time.Hour}, {duration: time.Hour}}
+	m.startTime = time.Now().Add(-time.Minute)
+	m.ticking = true // The loop started with the first step is still pending
+
+	next, cmd := m.Update(completeMsg{startTime: m.startTime, totalTime: m.totalTime})
+	m = next.(model)
+	if m.planIndex != 1 || !m.isRunning {
+		t.Fatalf("planIndex %d, running %v; want the second step running", m.planIndex, m.isRunning)
+	}
+	if n := countTicks(cmd, time.Second); n != 0 {
+		t.Errorf("completeMsg started %d more tick loops, want 0 while one is running", n)
+	}
+
+	// Ending through the tick itself lets that loop go, so the next step gets a new one
+	m.ticking = true
+	m.startTime = time.Now().Add(-time.Hour)
+	next, cmd = m.Update(tickMsg(time.Now()))
+	m = next.(model)
+	if n := countTicks(cmd, time.Second); n != 1 {
+		t.Errorf("finishing on a tick started %d tick loops, want 1", n)
+	}
+	if !m.ticking {
+		t.Error("ticking = false with a tick loop running")
+	}
+}
+
+func TestPasteIsNotKeys(t *testing.T) {
+	m := newTestModel(25*time.Minute, 10*time.Minute)
+	for _, text := range []string{"qr", "q", "r", ":quit"} {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+		got := next.(model)
+		if cmd != nil {
+			t.Errorf("pasting %q returned a command, want none (no quit)", text)
+		}
+		if got.elapsedTime != m.elapsedTime || got.highlightKey != "" || got.commanding {
+			t.Errorf("pasting %q acted as keys: elapsed %v, highlight %q, command line open %v", text, got.elapsedTime, got.highlightKey, got.commanding)
+		}
+	}
+
+	// With the ':' command line open, a paste is typed into it
+	m.commanding = true
+	m.commandLine = "label "
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Deep work"), Paste: true})
+	if got := next.(model).commandLine; got != "label Deep work" {
+		t.Errorf("commandLine = %q, want the paste appended", got)
+	}
+}
+
+func TestFormatRemaining(t *testing.T) {
+	tests := []struct {
+		remaining time.Duration
+		want      string
+	}{
+		{0, "00:00"},
+		{4*time.Minute + 30*time.Second, "04:30"},
+		{-(2*time.Minute + 30*time.Second), "+02:30"},
+		{-75 * time.Minute, "+75:00"},
+	}
+	for _, tt := range tests {
+		if got := formatRemaining(tt.remaining); got != tt.want {
+			t.Errorf("formatRemaining(%v) = %q, want %q", tt.remaining, got, tt.want)
+		}
+	}
+}
+
+func TestFormatClockPastAnHour(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{60 * time.Minute, "60:00"},
+		{75 * time.Minute, "75:00"}, // Used to wrap to 15:00
+		{100*time.Minute + 5*time.Second, "100:05"},
+	}
+	for _, tt := range tests {
+		if got := formatClock(tt.d); got != tt.want {
+			t.Errorf("formatClock(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestTimerTextOvertime(t *testing.T) {
+	m := newTestModel(25*time.Minute, 27*time.Minute)
+	if got := m.timerText(); got != "00:00" {
+		t.Errorf("past the end without overtime: timerText = %q, want 00:00", got)
+	}
+	m.inOvertime = true
+	if got := m.timerText(); got != "+02:00" {
+		t.Errorf("in overtime: timerText = %q, want +02:00", got)
+	}
+}
+
+func TestTimerTextRounding(t *testing.T) {
+	tests := []struct {
+		remaining time.Duration
+		round     bool
+		want      string
+	}{
+		{4*time.Minute + 59400*time.Millisecond, false, "04:59"},
+		{4*time.Minute + 59600*time.Millisecond, false, "04:59"},
+		{4*time.Minute + 59400*time.Millisecond, true, "04:59"},
+		{4*time.Minute + 59600*time.Millisecond, true, "05:00"},
+	}
+	for _, tt := range tests {
+		m := newTestModel(25*time.Minute, 25*time.Minute-tt.remaining)
+		m.opts.roundDisplay = tt.round
+		if got := m.timerText(); got != tt.want {
+			t.Errorf("timerText at %v remaining, roundDisplay %v = %q, want %q", tt.remaining, tt.round, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationMaxMinutes(t *testing.T) {
+	old := maxMinutes
+	t.Cleanup(func() { maxMinutes = old })
+
+	tests := []struct {
+		max     int
+		input   string
+		want    time.Duration
+		wantErr string
+	}{
+		{99, "99:59", 99*time.Minute + 59*time.Second, ""},
+		{99, "100:00", 0, "minutes must be a number between 0 and 99"},
+		{180, "100:00", 100 * time.Minute, ""},
+		{180, "180:00", 180 * time.Minute, ""},
+		{180, "181:00", 0, "minutes must be a number between 0 and 180"},
+		{maxMinutesLimit, "999:59", 999*time.Minute + 59*time.Second, ""},
+		{maxMinutesLimit, "1000:00", 0, "minutes must be a number between 0 and 999"},
+	}
+	for _, tt := range tests {
+		maxMinutes = tt.max
+		got, err := parseDuration(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("max %d: parseDuration(%q) error = %v, want %q", tt.max, tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("max %d: parseDuration(%q) = %v, %v; want %v", tt.max, tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		ok    bool
+	}{
+		{"25:00", 25 * time.Minute, true},
+		{"0:30", 30 * time.Second, true},
+		{"00:00", 0, true},
+		{"99:59", 99*time.Minute + 59*time.Second, true},
+		{"5:60", 0, false},
+		{"-1:00", 0, false},
+		{"5:-1", 0, false},
+		{"25", 0, false},
+		{"1:2:3", 0, false},
+		{"ab:cd", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.input)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v, ok %v", tt.input, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+// suspendFor sends Ctrl+Z and then tea.ResumeMsg, with a gap of d between
+// them. Rather than sleeping, the clock is advanced by moving every
+// timestamp recorded so far back by d.
+func suspendFor(t *testing.T, m model, d time.Duration) model {
+	t.Helper()
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = next.(model)
+	m.startTime = m.startTime.Add(-d)
+	m.sessionStart = m.sessionStart.Add(-d)
+	m.suspendedAt = m.suspendedAt.Add(-d)
+	if m.isPaused {
+		m.pausedAt = m.pausedAt.Add(-d)
+	}
+	next, _ = m.Update(tea.ResumeMsg{})
+	return next.(model)
+}
+
+func TestSuspendGapIsNotCounted(t *testing.T) {
+	const gap = 10 * time.Minute
+	const slack = time.Second // Real time that passes while the test runs
+
+	m := newTestModel(25*time.Minute, 0)
+	m.startTime = time.Now().Add(-5 * time.Minute)
+	m = suspendFor(t, m, gap)
+	if !m.isPaused {
+		t.Fatal("Ctrl+Z did not pause the running timer")
+	}
+	m = pressP(t, m) // Resume by hand, as after fg
+	next, _ := m.Update(tickMsg(time.Now()))
+	if got := next.(model).elapsedTime; got < 5*time.Minute || got > 5*time.Minute+slack {
+		t.Errorf("elapsedTime = %v after a %v suspend, want about 5m0s", got, gap)
+	}
+
+	// Overtime keeps counting through Ctrl+Z, so the gap is skipped on resume
+	m = newTestModel(25*time.Minute, 27*time.Minute)
+	m.inOvertime = true
+	m.isRunning = false
+	m.startTime = time.Now().Add(-27 * time.Minute)
+	m = suspendFor(t, m, gap)
+	next, _ = m.Update(tickMsg(time.Now()))
+	if got := next.(model).elapsedTime; got < 27*time.Minute || got > 27*time.Minute+slack {
+		t.Errorf("overtime elapsedTime = %v after a %v suspend, want about 27m0s", got, gap)
+	}
+}
+
+func TestMuteIsSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("theme = \"dark\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(configEnv, path)
+
+	m := newTestModel(25*time.Minute, 0)
+	for _, want := range []string{"mute = true", "mute = false"} {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+		m = next.(model)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != "theme = \"dark\"\n"+want+"\n" {
+			t.Errorf("config after m:\n%s\nwant the theme kept and %q", got, want)
+		}
+	}
+}
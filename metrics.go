@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// metrics holds the values exposed on /metrics. The model updates it from
+// Update while the HTTP server reads it concurrently, hence the mutex.
+type metrics struct {
+	mu        sync.Mutex
+	remaining float64
+	progress  float64
+	completed int
+}
+
+// set records the current remaining time and progress.
+func (mt *metrics) set(remaining time.Duration, progress float64) {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	mt.remaining = remaining.Seconds()
+	mt.progress = progress
+}
+
+// incCompleted counts one naturally completed timer.
+func (mt *metrics) incCompleted() {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	mt.completed++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (mt *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mt.mu.Lock()
+	remaining, progress, completed := mt.remaining, mt.progress, mt.completed
+	mt.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP gopomotime_remaining_seconds Seconds left on the current timer.\n")
+	fmt.Fprintf(w, "# TYPE gopomotime_remaining_seconds gauge\n")
+	fmt.Fprintf(w, "gopomotime_remaining_seconds %g\n", remaining)
+	fmt.Fprintf(w, "# HELP gopomotime_progress_ratio Fraction of the current timer elapsed, from 0 to 1.\n")
+	fmt.Fprintf(w, "# TYPE gopomotime_progress_ratio gauge\n")
+	fmt.Fprintf(w, "gopomotime_progress_ratio %g\n", progress)
+	fmt.Fprintf(w, "# HELP gopomotime_completed_total Timers that ran to completion.\n")
+	fmt.Fprintf(w, "# TYPE gopomotime_completed_total counter\n")
+	fmt.Fprintf(w, "gopomotime_completed_total %d\n", completed)
+}
+
+// serveMetrics starts serving mt on addr in the background. The returned
+// function shuts the server down.
+func serveMetrics(addr string, mt *metrics) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", mt)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
+
+// publishMetrics copies the model's progress to the metrics endpoint, if enabled.
+func (m model) publishMetrics() {
+	if m.metrics == nil {
+		return
+	}
+	remaining := m.totalTime - m.elapsedTime
+	if remaining < 0 {
+		remaining = 0
+	}
+	m.metrics.set(remaining, m.progress())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// localDigits holds the glyphs for 0-9 in the locale's numbering system, set
+// by useLocalDigits for --local-digits. Nil keeps ASCII digits.
+var localDigits []string
+
+// useLocalDigits selects the digits of lang's default numbering system, e.g.
+// "٠١٢…" for Arabic. Locales that use ASCII digits leave the display unchanged.
+func useLocalDigits(lang string) {
+	p := message.NewPrinter(language.Make(lang))
+	digits := make([]string, 10)
+	for d := range digits {
+		digits[d] = p.Sprint(d)
+	}
+	localDigits = digits
+}
+
+// localizeDigits replaces the ASCII digits in s with the local ones.
+func localizeDigits(s string) string {
+	if localDigits == nil {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteString(localDigits[r-'0'])
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// timerCells splits timer into one entry per terminal column for the donut's
+// center row. A double-width glyph is followed by an empty entry, so the
+// columns after it stay aligned.
+func timerCells(timer string) []string {
+	var cells []string
+	for _, r := range timer {
+		cells = append(cells, string(r))
+		if lipgloss.Width(string(r)) > 1 {
+			cells = append(cells, "")
+		}
+	}
+	return cells
+}
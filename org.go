@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// orgDefaultHeading is used for sessions without a label.
+const orgDefaultHeading = "gopomotime"
+
+// orgTimestamp formats t as an inactive org-mode timestamp, e.g. [2024-05-01 Wed 09:30].
+func orgTimestamp(t time.Time) string {
+	return t.Format("[2006-01-02 Mon 15:04]")
+}
+
+// orgClockLine formats a clocked interval the way org-mode writes it.
+func orgClockLine(start, end time.Time) string {
+	// Org clocks to the minute, so measure between the truncated timestamps
+	d := end.Truncate(time.Minute).Sub(start.Truncate(time.Minute))
+	return fmt.Sprintf("CLOCK: %s--%s => %2d:%02d", orgTimestamp(start), orgTimestamp(end), int(d.Hours()), int(d.Minutes())%60)
+}
+
+// appendOrgClock records a clocked entry under the top-level heading named
+// label in the org file at path. The newest clock goes first under an existing
+// heading, as org-mode does; otherwise the heading is added at the end.
+func appendOrgClock(path, label string, start, end time.Time) error {
+	if label == "" {
+		label = orgDefaultHeading
+	}
+	heading := "* " + label
+	clock := "  " + orgClockLine(start, end)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+
+	found := false
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") == heading {
+			lines = append(lines[:i+1], append([]string{clock}, lines[i+1:]...)...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		lines = append(lines, heading, clock)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// phaseKind distinguishes work and break timers in a sequence.
+type phaseKind int
+
+const (
+	phaseNone phaseKind = iota // Plain timer (single duration or plan file)
+	phaseWork
+	phaseBreak
+	phaseCooldown // Wind-down after the last work phase (--cooldown)
+)
+
+// segment is one labeled timer in a sequence.
+type segment struct {
+	duration time.Duration
+	label    string
+	kind     phaseKind
+	category string // From a --tasks file, empty otherwise
+
+	commands []string // Plan file "!command" lines run before the timer starts
+}
+
+// loadPlan reads a plan file with one "mm:ss <label>" timer per line.
+// Lines starting with '!' are shell commands run before the next timer.
+// Blank lines and lines starting with '#' are ignored.
+func loadPlan(path string) ([]segment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var plan []segment
+	var commands []string
+	lastCommand := 0 // Line of the last command, reported if no timer follows it
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if command, ok := strings.CutPrefix(line, "!"); ok {
+			if command = strings.TrimSpace(command); command == "" {
+				return nil, fmt.Errorf("%s:%d: empty command", path, lineNo)
+			}
+			commands = append(commands, command)
+			lastCommand = lineNo
+			continue
+		}
+		field, label := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			field, label = line[:i], strings.TrimSpace(line[i:])
+		}
+		duration, err := parseDuration(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNo, err)
+		}
+		plan = append(plan, segment{duration: duration, label: label, commands: commands})
+		commands = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(commands) > 0 {
+		return nil, fmt.Errorf("%s:%d: command must be followed by a timer", path, lastCommand)
+	}
+	if len(plan) == 0 {
+		return nil, fmt.Errorf("%s: no timers found", path)
+	}
+	return plan, nil
+}
+
+// intervalPlan builds alternating work and rest phases from a "work/rest" spec
+// such as "25:00/5:00". The final rest is left out so the sequence ends on work.
+func intervalPlan(spec string, rounds int, rng *rand.Rand) ([]segment, error) {
+	workSpec, restSpec, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid interval %q, expected work/rest (e.g. 25:00/5:00)", spec)
+	}
+	return pomodoroPlan(workSpec, restSpec, rounds, rng)
+}
+
+// pomodoroPlan builds alternating work and rest phases, as intervalPlan does,
+// from separate work and rest specs. Either may be a range such as
+// "20:00-30:00", in which case each phase gets its own duration drawn from rng.
+func pomodoroPlan(workSpec, restSpec string, rounds int, rng *rand.Rand) ([]segment, error) {
+	work, err := parseRange(workSpec)
+	if err != nil {
+		return nil, fmt.Errorf("work: %v", err)
+	}
+	rest, err := parseRange(restSpec)
+	if err != nil {
+		return nil, fmt.Errorf("rest: %v", err)
+	}
+	if rounds < 1 {
+		return nil, fmt.Errorf("rounds must be at least 1")
+	}
+
+	var plan []segment
+	for i := 1; i <= rounds; i++ {
+		plan = append(plan, segment{duration: work.pick(rng), label: fmt.Sprintf("Work %d/%d", i, rounds), kind: phaseWork})
+		if i < rounds {
+			plan = append(plan, segment{duration: rest.pick(rng), label: fmt.Sprintf("Rest %d/%d", i, rounds), kind: phaseBreak})
+		}
+	}
+	return plan, nil
+}
+
+// addLongBreaks lengthens the rest after every every-th work phase to long
+// (--long-break), so a four-round cycle gets its traditional longer break.
+// The plan has no rest after its last work phase, so when that phase closes a
+// cycle a long rest is added at the end, as with the default 4 rounds.
+func addLongBreaks(plan []segment, long time.Duration, every int) []segment {
+	works := 0
+	for i := range plan {
+		switch plan[i].kind {
+		case phaseWork:
+			works++
+		case phaseBreak:
+			if works%every == 0 {
+				plan[i].duration = long
+				plan[i].label = "Long " + strings.ToLower(plan[i].label)
+			}
+		}
+	}
+	if works > 0 && works%every == 0 && plan[len(plan)-1].kind == phaseWork {
+		plan = append(plan, segment{duration: long, label: fmt.Sprintf("Long rest %d/%d", works, works), kind: phaseBreak})
+	}
+	return plan
+}
+
+// durationRange is a phase length given as "mm:ss" or "mm:ss-mm:ss".
+type durationRange struct {
+	low, high time.Duration
+}
+
+// parseRange parses a single duration or a low-high range of durations.
+func parseRange(spec string) (durationRange, error) {
+	lowSpec, highSpec, isRange := strings.Cut(spec, "-")
+	low, err := parseDuration(lowSpec)
+	if err != nil || !isRange {
+		return durationRange{low, low}, err
+	}
+	high, err := parseDuration(highSpec)
+	if err != nil {
+		return durationRange{}, err
+	}
+	if high < low {
+		return durationRange{}, fmt.Errorf("invalid range %q, the end comes before the start", spec)
+	}
+	return durationRange{low, high}, nil
+}
+
+// pick returns a duration in the range, in whole seconds, chosen with rng.
+func (r durationRange) pick(rng *rand.Rand) time.Duration {
+	seconds := int64((r.high - r.low) / time.Second)
+	if seconds == 0 {
+		return r.low
+	}
+	return r.low + time.Duration(rng.Int63n(seconds+1))*time.Second
+}
+
+// startSegment switches the model to the plan entry at index i and starts it running.
+func (m *model) startSegment(i int) {
+	now := time.Now()
+	m.planIndex = i
+	m.totalTime = m.plan[i].duration
+	m.label = m.plan[i].label
+	m.elapsedTime = 0
+	m.isRunning = true
+	m.isPaused = false
+	m.startTime = now
+	m.sessionStart = now
+	m.logged = false
+	m.loggedBlocks = 0
+	m.pauseCount = 0
+	m.pausedTime = 0
+	m.spoken = 0
+	m.category = m.plan[i].category // Each step is tagged on its own
+}
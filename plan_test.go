@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddLongBreaks(t *testing.T) {
+	tests := []struct {
+		name          string
+		rounds, every int
+		want          []string // Labels of the long rests
+	}{
+		{"defaults", 4, 4, []string{"Long rest 4/4"}},
+		{"two cycles", 8, 4, []string{"Long rest 4/8", "Long rest 8/8"}},
+		{"mid-cycle end", 6, 4, []string{"Long rest 4/6"}},
+		{"every round", 2, 1, []string{"Long rest 1/2", "Long rest 2/2"}},
+		{"too few rounds", 3, 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := pomodoroPlan("25:00", "5:00", tt.rounds, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan = addLongBreaks(plan, 15*time.Minute, tt.every)
+
+			var got []string
+			for _, s := range plan {
+				if s.duration == 15*time.Minute {
+					if s.kind != phaseBreak {
+						t.Errorf("%q is long but not a break", s.label)
+					}
+					got = append(got, s.label)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("long rests %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("long rest %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
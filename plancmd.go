@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// planCommandMsg reports how a plan file "!command" finished.
+type planCommandMsg struct {
+	err error
+}
+
+// planCommandCmd runs a plan command with sh, off the UI goroutine. Like
+// --exec, it gets the upcoming timer's label and length in GOPOMO_LABEL and
+// GOPOMO_DURATION, and its output is discarded.
+func planCommandCmd(command, label string, duration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(), "GOPOMO_LABEL="+label, "GOPOMO_DURATION="+formatClock(duration))
+		return planCommandMsg{err: cmd.Run()}
+	}
+}
+
+// queueCommands holds a timer that has just started while the commands before
+// it in the plan run, one after another. The hold is a pause that is not the
+// user's, so it is not counted in the session's paused time.
+func (m *model) queueCommands() {
+	if m.plan == nil || !m.isRunning || len(m.plan[m.planIndex].commands) == 0 {
+		return
+	}
+	m.commandQueue = m.plan[m.planIndex].commands
+	m.commandFailed = ""
+	m.isPaused = true
+	m.pausedAt = time.Now()
+}
+
+// commandCmd runs the first queued command, if any.
+func (m model) commandCmd() tea.Cmd {
+	if len(m.commandQueue) == 0 {
+		return nil
+	}
+	return planCommandCmd(m.commandQueue[0], m.label, m.totalTime)
+}
+
+// nextCommand drops the command just run and starts the next one, or starts
+// the timer once none are left.
+func (m model) nextCommand() (tea.Model, tea.Cmd) {
+	m.commandQueue = m.commandQueue[1:]
+	m.commandFailed = ""
+	if len(m.commandQueue) > 0 {
+		return m, m.commandCmd()
+	}
+	now := time.Now()
+	m.isPaused = false
+	m.startTime = now.Add(-m.elapsedTime)
+	m.sessionStart = now
+	return m, tea.Batch(m.startTick(), m.completeCmd())
+}
+
+// commandStatus is the status line while plan commands hold the timer.
+func (m model) commandStatus() string {
+	if m.commandFailed != "" {
+		return truncateText(msgs.Failed+" "+m.commandQueue[0], width) + "\n" + truncateText(m.commandFailed, width) + "\n" + msgs.CommandPrompt
+	}
+	return truncateText(msgs.Running+" "+m.commandQueue[0], width)
+}
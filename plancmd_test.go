@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStartWithPRunsPlanCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *model)
+	}{
+		{"clock-then-donut", func(m *model) { m.opts.clockThenDonut = true }},
+		{"early start during --at", func(m *model) { m.waitUntil = time.Now().Add(time.Hour) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(5*time.Minute, 0)
+			m.plan = []segment{{duration: 5 * time.Minute, commands: []string{"true"}}}
+			m.isRunning = false
+			tt.setup(&m)
+			m.queueCommands() // As main does; nothing is queued before the start
+
+			m = pressP(t, m)
+			if !m.isRunning || !m.isPaused {
+				t.Errorf("running %v, paused %v; want the start held for the commands", m.isRunning, m.isPaused)
+			}
+			if len(m.commandQueue) != 1 {
+				t.Errorf("commandQueue = %q, want the plan's command", m.commandQueue)
+			}
+		})
+	}
+}
+
+func TestCommandHoldIsNotLoggedAsPaused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	m := newTestModel(5*time.Minute, 0)
+	m.opts.historyPath = path
+	m.plan = []segment{{duration: 5 * time.Minute, commands: []string{"sleep 60"}}}
+	m.startTime = time.Now()
+	m.sessionStart = m.startTime
+	m.queueCommands()
+	m.pausedAt = time.Now().Add(-time.Minute) // Held for a minute already
+
+	m.logSession()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry historyEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.PausedSeconds != 0 {
+		t.Errorf("paused_seconds = %d, want 0 while commands hold the timer", entry.PausedSeconds)
+	}
+}
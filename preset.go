@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// presetPrefix marks config keys that define a preset rather than set a flag,
+// e.g. preset-deep = "50:00 Deep work".
+const presetPrefix = "preset-"
+
+// preset is a named timer from the config file, started with --preset or
+// picked at runtime with P.
+type preset struct {
+	name     string
+	duration time.Duration
+	label    string
+}
+
+// parsePreset parses a preset value, "mm:ss" optionally followed by a label.
+func parsePreset(name, value string) (preset, error) {
+	field, label, _ := strings.Cut(strings.TrimSpace(value), " ")
+	duration, err := parseDuration(field)
+	if err != nil {
+		return preset{}, err
+	}
+	return preset{name: name, duration: duration, label: strings.TrimSpace(label)}, nil
+}
+
+// loadPresets returns the presets defined in the config file, in file order.
+// A config file with errors has already been reported, so it yields none.
+func loadPresets() []preset {
+	path, source := configPath()
+	if path == "" {
+		return nil
+	}
+	entries, err := parseConfig(path, source == "$"+configEnv)
+	if err != nil {
+		return nil
+	}
+	var presets []preset
+	for _, e := range entries {
+		if name, ok := strings.CutPrefix(e.key, presetPrefix); ok {
+			if p, err := parsePreset(name, e.value); err == nil {
+				presets = append(presets, p)
+			}
+		}
+	}
+	return presets
+}
+
+// findPreset looks up a preset by name.
+func findPreset(presets []preset, name string) (preset, error) {
+	var names []string
+	for _, p := range presets {
+		if p.name == name {
+			return p, nil
+		}
+		names = append(names, p.name)
+	}
+	if len(names) == 0 {
+		return preset{}, fmt.Errorf("unknown preset %q; define it in config.toml as %s%s = \"mm:ss label\"", name, presetPrefix, name)
+	}
+	return preset{}, fmt.Errorf("unknown preset %q (config.toml defines %s)", name, strings.Join(names, ", "))
+}
+
+// presetLine describes the preset picked with P for the status line; its
+// label shows above the donut once it starts.
+func (m model) presetLine() string {
+	p := m.presets[m.presetIndex]
+	return truncateText(p.name, width-16) + " " + formatClock(p.duration) + " · ⏎ start"
+}
+
+// startPreset replaces the finished or stopped timer with the picked preset
+// and starts it.
+func (m model) startPreset() (tea.Model, tea.Cmd) {
+	p := m.presets[m.presetIndex]
+	m.pickingPreset = false
+	m.plan = []segment{{duration: p.duration, label: p.label}}
+	m.inOvertime = false
+	m.quote = ""
+	m.goalReached = false
+	m.commandQueue = nil // The old plan's commands don't hold the preset
+	m.commandFailed = ""
+	m.startSegment(0)
+	return m, tea.Batch(m.startTick(), m.completeCmd())
+}
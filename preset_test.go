@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStartPresetStartsClean(t *testing.T) {
+	m := newTestModel(25*time.Minute, 25*time.Minute)
+	m.isRunning = false
+	m.plan = []segment{{duration: 25 * time.Minute, commands: []string{"false"}}}
+	m.presets = []preset{{name: "tea", duration: 4 * time.Minute, label: "Tea"}}
+	m.goalReached = true
+	m.commandQueue = []string{"false"}
+	m.commandFailed = "exit status 1"
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("P")}, {Type: tea.KeyEnter}} {
+		next, _ := m.Update(key)
+		m = next.(model)
+	}
+	if !m.isRunning || m.isPaused || m.label != "Tea" {
+		t.Fatalf("running %v, paused %v, label %q; want the preset running", m.isRunning, m.isPaused, m.label)
+	}
+	if m.goalReached {
+		t.Error("goalReached carried over from the previous timer")
+	}
+	if len(m.commandQueue) > 0 || m.commandFailed != "" {
+		t.Errorf("commandQueue %q, commandFailed %q; want the old plan's commands dropped", m.commandQueue, m.commandFailed)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxPromptInput caps the length of the typed duration.
+const maxPromptInput = 8
+
+// promptModel asks for a duration when none is given on the command line.
+type promptModel struct {
+	input     string
+	err       error
+	duration  time.Duration
+	done      bool // A valid duration was entered
+	cancelled bool // The user quit with Ctrl+C
+}
+
+// Init starts the prompt without any commands.
+func (m promptModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles typing, editing, and submitting the duration.
+func (m promptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.cancelled = true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		// Validate with the same rules as the command-line argument
+		duration, err := parseDuration(m.input)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.duration = duration
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+		m.err = nil
+	case tea.KeyRunes:
+		// Accept only digits and the separator
+		for _, r := range key.Runes {
+			if (r >= '0' && r <= '9' || r == ':') && len(m.input) < maxPromptInput {
+				m.input += string(r)
+			}
+		}
+		m.err = nil
+	}
+	return m, nil
+}
+
+// View renders the prompt, the typed input, and any validation error.
+func (m promptModel) View() string {
+	lines := []string{
+		"Timer duration (mm:ss):",
+		"> " + m.input + highlightStyle.Render("_"),
+		"",
+		"[enter] start [ctrl+c] quit",
+	}
+	if m.err != nil {
+		lines[2] = redStyle.Render(m.err.Error())
+	}
+
+	leftPadding := strings.Repeat(" ", 4)
+	return leftPadding + strings.Join(lines, "\n"+leftPadding)
+}
+
+// promptDuration runs the interactive prompt and returns the entered duration.
+// ok is false if the user cancelled.
+func promptDuration() (duration time.Duration, ok bool, err error) {
+	final, err := tea.NewProgram(promptModel{}, tuiOptions()...).Run()
+	if err != nil {
+		return 0, false, err
+	}
+	m := final.(promptModel)
+	return m.duration, m.done, nil
+}
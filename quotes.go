@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxQuoteLines caps how many wrapped lines of a quote are shown.
+const maxQuoteLines = 3
+
+// loadQuotes reads one quote per non-blank line from path.
+func loadQuotes(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var quotes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			quotes = append(quotes, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("%s: no quotes found", path)
+	}
+	return quotes, nil
+}
+
+// pickQuote returns a random quote using rng, so a seeded source gives a
+// repeatable choice. It returns "" when there are no quotes.
+func pickQuote(quotes []string, rng *rand.Rand) string {
+	if len(quotes) == 0 {
+		return ""
+	}
+	return quotes[rng.Intn(len(quotes))]
+}
+
+// wrapText breaks s into lines of at most n columns on word boundaries,
+// splitting words that are longer than a whole line. Width is measured as
+// displayed, so wide characters (CJK, emoji) count as two columns and text
+// without spaces is broken between characters.
+func wrapText(s string, n int) []string {
+	var lines []string
+	line, lineWidth := "", 0
+	for _, word := range strings.Fields(s) {
+		wordWidth := lipgloss.Width(word)
+		if lineWidth > 0 && lineWidth+1+wordWidth <= n {
+			line, lineWidth = line+" "+word, lineWidth+1+wordWidth
+			continue
+		}
+		if lineWidth > 0 {
+			lines = append(lines, line)
+			line, lineWidth = "", 0
+		}
+		for _, r := range word {
+			w := lipgloss.Width(string(r))
+			if lineWidth > 0 && lineWidth+w > n {
+				lines = append(lines, line)
+				line, lineWidth = "", 0
+			}
+			line, lineWidth = line+string(r), lineWidth+w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// quoteLines wraps the finished-screen quote to the donut width, truncating
+// the last line if the quote runs past maxQuoteLines.
+func (m model) quoteLines() []string {
+	if m.quote == "" {
+		return nil
+	}
+	lines := wrapText(m.quote, width)
+	if len(lines) > maxQuoteLines {
+		lines = lines[:maxQuoteLines]
+		lines[maxQuoteLines-1] = truncateText(lines[maxQuoteLines-1]+" …", width)
+	}
+	return lines
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// macTerminalApps maps $TERM_PROGRAM to the application AppleScript should activate.
+var macTerminalApps = map[string]string{
+	"Apple_Terminal": "Terminal",
+	"iTerm.app":      "iTerm",
+	"WezTerm":        "WezTerm",
+	"ghostty":        "Ghostty",
+}
+
+// raiseWindow tries to bring the terminal window to the front (--raise), using
+// AppleScript on macOS and wmctrl or xdotool with $WINDOWID on X11. It does
+// nothing when the terminal or tool cannot be identified, and ignores errors.
+func raiseWindow() {
+	switch runtime.GOOS {
+	case "darwin":
+		if app, ok := macTerminalApps[os.Getenv("TERM_PROGRAM")]; ok {
+			_ = exec.Command("osascript", "-e", `tell application "`+app+`" to activate`).Run()
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		id := os.Getenv("WINDOWID") // Set by xterm, urxvt, kitty, Alacritty and others
+		if id == "" {
+			return
+		}
+		if _, err := exec.LookPath("wmctrl"); err == nil {
+			_ = exec.Command("wmctrl", "-i", "-a", id).Run()
+			return
+		}
+		_ = exec.Command("xdotool", "windowactivate", id).Run()
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// recordInterval is the minimum time between frames written by --record.
+const recordInterval = time.Second
+
+// frameSeparator ends each frame in a --record file: a form feed on its own line.
+const frameSeparator = "\f\n"
+
+// frameRecorder appends rendered frames to a file (--record). It is shared by
+// pointer so the throttle survives model copies.
+type frameRecorder struct {
+	f    *os.File
+	last time.Time
+	prev string
+}
+
+// newFrameRecorder creates (or truncates) the recording file at path.
+func newFrameRecorder(path string) (*frameRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &frameRecorder{f: f}, nil
+}
+
+// record writes the current view, at most once per recordInterval and only
+// when it differs from the previous frame. Write errors are ignored.
+func (r *frameRecorder) record(m model) {
+	now := time.Now()
+	if now.Sub(r.last) < recordInterval {
+		return
+	}
+	frame := m.View()
+	if frame == r.prev {
+		return
+	}
+	r.last, r.prev = now, frame
+	_, _ = r.f.WriteString(frame + "\n" + frameSeparator)
+}
+
+// close flushes and closes the recording file.
+func (r *frameRecorder) close() {
+	r.f.Close()
+}
@@ -0,0 +1,12 @@
+package main
+
+// focusScore rates a session for --score: 100, less the pause penalty for each
+// pause and the incomplete penalty if the timer was stopped early, never
+// below 0. A stopwatch cannot be stopped early.
+func (m model) focusScore() int {
+	score := 100 - m.pauseCount*m.opts.pausePenalty
+	if m.elapsedTime < m.totalTime && !m.opts.stopwatch {
+		score -= m.opts.incompletePenalty
+	}
+	return max(score, 0)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Snapshot geometry, in SVG user units
+const (
+	snapshotCharWidth  = 10
+	snapshotLineHeight = 20
+	snapshotFontSize   = 16
+	snapshotMargin     = 20
+)
+
+// svgColor returns the foreground of style as an SVG color, falling back to def.
+func svgColor(style lipgloss.Style, def lipgloss.Color) string {
+	if c, ok := style.GetForeground().(lipgloss.Color); ok && c != "" {
+		return string(c)
+	}
+	return string(def)
+}
+
+// writeSnapshot saves the completed donut and session stats to path as an SVG image.
+// Each row of the donut becomes a <text> element with one colored <tspan> per cell.
+func writeSnapshot(path string, m model) error {
+	var rows []string
+	for _, row := range donutCells(1, formatClock(m.totalTime), ringOptions{timerStyle: whiteStyle, secondHand: -1, celebrate: -1, breath: -1}) {
+		var b strings.Builder
+		for _, c := range row {
+			if !c.styled {
+				b.WriteString(html.EscapeString(c.char))
+				continue
+			}
+			fmt.Fprintf(&b, `<tspan fill="%s">%s</tspan>`, svgColor(c.style, activePalette.elapsed), html.EscapeString(c.char))
+		}
+		rows = append(rows, b.String())
+	}
+
+	// Stats under the donut
+	stats := []string{fmt.Sprintf(`<tspan fill="%s">Timer finished!</tspan>`, activePalette.finished)}
+	if m.label != "" {
+		stats = append(stats, html.EscapeString(m.label))
+	}
+	stats = append(stats, "Focused for "+formatClock(m.totalTime), time.Now().Format("2006-01-02 15:04"))
+	rows = append(rows, stats...)
+
+	w := width*snapshotCharWidth + 2*snapshotMargin
+	h := (len(rows)+1)*snapshotLineHeight + 2*snapshotMargin
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", w, h, w, h)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", activePalette.background)
+	fmt.Fprintf(&b, `<g font-family="monospace" font-size="%d" fill="%s" xml:space="preserve">`+"\n", snapshotFontSize, activePalette.elapsed)
+	for i, row := range rows {
+		y := snapshotMargin + (i+1)*snapshotLineHeight
+		if i >= height {
+			y += snapshotLineHeight // Gap between donut and stats
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", w/2, y, row)
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", snapshotMargin, y, row)
+	}
+	b.WriteString("</g>\n</svg>\n")
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
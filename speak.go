@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// speakers are the text-to-speech commands tried for --speak, in order.
+var speakers = map[string][]string{
+	"darwin": {"say"},
+	"linux":  {"spd-say", "espeak"},
+}
+
+// speakCmd returns a Bubble Tea command that reads text aloud with the first
+// text-to-speech tool found, doing nothing when there is none.
+func speakCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		for _, name := range speakers[runtime.GOOS] {
+			if _, err := exec.LookPath(name); err == nil {
+				_ = exec.Command(name, text).Run()
+				break
+			}
+		}
+		return nil
+	}
+}
+
+// parseMilestones parses the --speak-at list, e.g. "5m,1m,10s", into
+// durations sorted from the longest down.
+func parseMilestones(list string) ([]time.Duration, error) {
+	var milestones []time.Duration
+	for _, field := range strings.Split(list, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(field))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid --speak-at time %q, expected e.g. 5m or 30s", field)
+		}
+		milestones = append(milestones, d)
+	}
+	slices.Sort(milestones)
+	slices.Reverse(milestones)
+	return milestones, nil
+}
+
+// milestoneText words a milestone for speaking, e.g. "5 minutes remaining".
+func milestoneText(d time.Duration) string {
+	n, unit := int(d.Seconds()), "second"
+	if d%time.Minute == 0 {
+		n, unit = int(d.Minutes()), "minute"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s remaining", n, unit)
+}
+
+// announce returns the command speaking the latest --speak milestone passed
+// with remaining time left, if any. Milestones are announced once per timer,
+// and ones that are not shorter than the timer itself are skipped.
+func (m *model) announce(remaining time.Duration) tea.Cmd {
+	text := ""
+	for m.spoken < len(m.opts.speakAt) && remaining <= m.opts.speakAt[m.spoken] {
+		if m.opts.speakAt[m.spoken] < m.totalTime {
+			text = milestoneText(m.opts.speakAt[m.spoken])
+		}
+		m.spoken++
+	}
+	if text == "" || !m.soundAllowed() {
+		return nil
+	}
+	return speakCmd(text)
+}
@@ -0,0 +1,30 @@
+//go:build !windows && !plan9
+
+package main
+
+import "log/syslog"
+
+// systemLog writes --syslog entries to the system logger, which journald
+// also collects on systemd machines.
+type systemLog struct {
+	w *syslog.Writer
+}
+
+// openSystemLog connects to the local system logger.
+func openSystemLog() (*systemLog, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, "gopomotime")
+	if err != nil {
+		return nil, err
+	}
+	return &systemLog{w: w}, nil
+}
+
+// info writes one informational entry, ignoring failures like the history log.
+func (l *systemLog) info(msg string) {
+	_ = l.w.Info(msg)
+}
+
+// close disconnects from the system logger.
+func (l *systemLog) close() {
+	l.w.Close()
+}
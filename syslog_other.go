@@ -0,0 +1,17 @@
+//go:build windows || plan9
+
+package main
+
+import "errors"
+
+// systemLog stands in for the system logger where log/syslog is unavailable.
+type systemLog struct{}
+
+// openSystemLog reports that there is no system logger to write to.
+func openSystemLog() (*systemLog, error) {
+	return nil, errors.New("the system log is not available on this platform")
+}
+
+func (l *systemLog) info(msg string) {}
+
+func (l *systemLog) close() {}
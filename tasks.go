@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// task is one entry of a --tasks file.
+type task struct {
+	Duration string `json:"duration"`
+	Label    string `json:"label"`
+	Category string `json:"category"`
+}
+
+// loadTasks reads a --tasks file: a JSON array of objects with a "duration"
+// ("mm:ss") and optional "label" and "category", run in order like a plan.
+// Unknown fields are rejected so typos are caught rather than ignored.
+func loadTasks(path string) ([]segment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var tasks []task
+	if err := dec.Decode(&tasks); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, fmt.Errorf("%s: invalid JSON at byte %d: %v", path, syntaxErr.Offset, err)
+		case errors.As(err, &typeErr) && typeErr.Field == "":
+			return nil, fmt.Errorf("%s: expected an array of tasks", path)
+		case errors.As(err, &typeErr):
+			// Field is e.g. "0.duration": the task's index, then the key
+			index, key, _ := strings.Cut(typeErr.Field, ".")
+			n, _ := strconv.Atoi(index)
+			return nil, fmt.Errorf("%s: task %d: %q must be a string", path, n+1, key)
+		}
+		return nil, fmt.Errorf("%s: %s", path, strings.TrimPrefix(err.Error(), "json: "))
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%s: unexpected data after the array of tasks", path)
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("%s: no tasks found", path)
+	}
+
+	plan := make([]segment, 0, len(tasks))
+	for i, t := range tasks {
+		if t.Duration == "" {
+			return nil, fmt.Errorf("%s: task %d: missing \"duration\"", path, i+1)
+		}
+		duration, err := parseDuration(t.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("%s: task %d: duration %q: %v", path, i+1, t.Duration, err)
+		}
+		plan = append(plan, segment{duration: duration, label: strings.TrimSpace(t.Label), category: strings.TrimSpace(t.Category)})
+	}
+	return plan, nil
+}
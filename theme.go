@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// monochrome is set when the terminal cannot show color, so feedback falls back to text markers.
+var monochrome bool
+
+// palette is the set of colors used to draw the donut and status text.
+type palette struct {
+	elapsed      lipgloss.Color
+	remaining    lipgloss.Color
+	finished     lipgloss.Color
+	highlight    lipgloss.Color
+	overtime     lipgloss.Color
+	hand         lipgloss.Color
+	dimElapsed   lipgloss.Color
+	dimRemaining lipgloss.Color
+	finale       lipgloss.Color // Bright phase of the --finale pulse
+	background   lipgloss.Color // Only used for --snapshot images
+
+	finishedMark string // Put before the finished message so it doesn't rely on color alone
+}
+
+// activePalette is the palette last applied, for output that is not styled through lipgloss.
+var activePalette = palettes["dark"]
+
+// palettes maps each --theme name to its colors.
+var palettes = map[string]palette{
+	"dark": {
+		elapsed:      "#FFFFFF",
+		remaining:    "#FF0000",
+		finished:     "#00FF00",
+		highlight:    "#FFa400",
+		overtime:     "#FFD700",
+		hand:         "#00BFFF",
+		dimElapsed:   "#808080",
+		dimRemaining: "#8B0000",
+		finale:       "#FF6060",
+		background:   "#000000",
+	},
+	"light": {
+		elapsed:      "#303030",
+		remaining:    "#CC0000",
+		finished:     "#008000",
+		highlight:    "#D75F00",
+		overtime:     "#B8860B",
+		hand:         "#0070C0",
+		dimElapsed:   "#A8A8A8",
+		dimRemaining: "#E08080",
+		finale:       "#FF3030",
+		background:   "#FFFFFF",
+	},
+	// Blue and orange from the Okabe-Ito set, with no red/green contrast
+	"colorblind": {
+		elapsed:      "#FFFFFF",
+		remaining:    "#E69F00",
+		finished:     "#56B4E9",
+		highlight:    "#F0E442",
+		overtime:     "#CC79A7",
+		hand:         "#56B4E9",
+		dimElapsed:   "#808080",
+		dimRemaining: "#805800",
+		finale:       "#FFC34D",
+		background:   "#000000",
+		finishedMark: "✔ ",
+	},
+}
+
+// resolveTheme returns the palette name for the --theme value, detecting the
+// terminal background when it is "auto".
+func resolveTheme(name string) (string, error) {
+	if name == "auto" {
+		if terminalIsDark() {
+			return "dark", nil
+		}
+		return "light", nil
+	}
+	if _, ok := palettes[name]; !ok {
+		return "", fmt.Errorf("unknown theme %q", name)
+	}
+	return name, nil
+}
+
+// terminalIsDark reports whether the terminal has a dark background, using
+// COLORFGBG when the terminal sets it and querying the terminal otherwise.
+func terminalIsDark() bool {
+	if fgbg := os.Getenv("COLORFGBG"); fgbg != "" {
+		fields := strings.Split(fgbg, ";")
+		if bg, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			return bg < 7 || bg == 8 // ANSI 7 and 9-15 are light backgrounds
+		}
+	}
+	return lipgloss.HasDarkBackground()
+}
+
+// cometLevels is the number of brightness steps in the --comet tail.
+const cometLevels = 8
+
+// cometStyles runs from the dimmed to the full elapsed color, set by applyPalette.
+var cometStyles []lipgloss.Style
+
+// blendColors mixes two "#RRGGBB" colors, returning a at t=0 and b at t=1.
+func blendColors(a, b lipgloss.Color, t float64) lipgloss.Color {
+	var ar, ag, ab, br, bg, bb int
+	fmt.Sscanf(string(a), "#%02x%02x%02x", &ar, &ag, &ab)
+	fmt.Sscanf(string(b), "#%02x%02x%02x", &br, &bg, &bb)
+	mix := func(x, y int) int { return x + int(float64(y-x)*t) }
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", mix(ar, br), mix(ag, bg), mix(ab, bb)))
+}
+
+// applyPalette sets the package-level styles from p.
+func applyPalette(p palette) {
+	activePalette = p
+	whiteStyle = lipgloss.NewStyle().Foreground(p.elapsed)
+	redStyle = lipgloss.NewStyle().Foreground(p.remaining)
+	greenStyle = lipgloss.NewStyle().Foreground(p.finished)
+	highlightStyle = lipgloss.NewStyle().Foreground(p.highlight)
+	overtimeStyle = lipgloss.NewStyle().Foreground(p.overtime)
+	handStyle = lipgloss.NewStyle().Foreground(p.hand)
+	dimWhiteStyle = lipgloss.NewStyle().Foreground(p.dimElapsed)
+	dimRedStyle = lipgloss.NewStyle().Foreground(p.dimRemaining)
+	finaleStyle = lipgloss.NewStyle().Foreground(p.finale)
+
+	cometStyles = make([]lipgloss.Style, cometLevels)
+	for i := range cometStyles {
+		t := float64(i) / float64(cometLevels-1)
+		cometStyles[i] = lipgloss.NewStyle().Foreground(blendColors(p.dimElapsed, p.elapsed, t))
+	}
+}
+
+// isHexColor reports whether s is a "#RRGGBB" color, the form blendColors reads.
+func isHexColor(s string) bool {
+	if len(s) != 7 || s[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(s[1:], 16, 32)
+	return err == nil
+}
+
+// detectMonochrome reports whether the terminal lacks color support.
+func detectMonochrome() bool {
+	return os.Getenv("TERM") == "dumb" || lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// markKey highlights a control label, using >label< markers on monochrome terminals.
+func markKey(label string) string {
+	if monochrome {
+		return ">" + label + "<"
+	}
+	return highlightStyle.Render(label)
+}
+
+// Night hours for the automatic evening palette: from nightStart until dayStart.
+const (
+	nightStart = 20
+	dayStart   = 7
+)
+
+// nightDim is how far the evening palette blends each color toward the background.
+const nightDim = 0.35
+
+// isNight reports whether hour falls in the evening/night range.
+func isNight(hour int) bool {
+	return hour >= nightStart || hour < dayStart
+}
+
+// nightPalette returns a dimmer version of p, for easier evening sessions.
+func nightPalette(p palette) palette {
+	dim := func(c lipgloss.Color) lipgloss.Color { return blendColors(c, p.background, nightDim) }
+	return palette{
+		elapsed:      dim(p.elapsed),
+		remaining:    dim(p.remaining),
+		finished:     dim(p.finished),
+		highlight:    dim(p.highlight),
+		overtime:     dim(p.overtime),
+		hand:         dim(p.hand),
+		dimElapsed:   dim(p.dimElapsed),
+		dimRemaining: dim(p.dimRemaining),
+		finale:       dim(p.finale),
+		background:   p.background,
+	}
+}
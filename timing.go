@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// tickTiming tracks how far apart tickMsgs actually arrive, for --debug-timing.
+// Jitter is the gap between deliveries less tickRate, so late ticks are positive.
+type tickTiming struct {
+	last          time.Time // Previous delivery, zero after the tick loop has lapsed
+	n             int
+	min, max, sum time.Duration
+}
+
+// record notes a tick delivered at now.
+func (t *tickTiming) record(now time.Time) {
+	if !t.last.IsZero() {
+		jitter := now.Sub(t.last) - tickRate
+		if t.n == 0 || jitter < t.min {
+			t.min = jitter
+		}
+		if t.n == 0 || jitter > t.max {
+			t.max = jitter
+		}
+		t.sum += jitter
+		t.n++
+	}
+	t.last = now
+}
+
+// pause forgets the last delivery when the tick loop lapses (paused or
+// finished), so the gap until it restarts is not counted.
+func (t *tickTiming) pause() {
+	t.last = time.Time{}
+}
+
+// String shows min/avg/max jitter in milliseconds, e.g. "jitter +1/+3/+20ms".
+func (t *tickTiming) String() string {
+	if t.n == 0 {
+		return "jitter -/-/-ms"
+	}
+	avg := t.sum / time.Duration(t.n)
+	return fmt.Sprintf("jitter %+d/%+d/%+dms", t.min.Milliseconds(), avg.Milliseconds(), t.max.Milliseconds())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// titlePlaceholder matches one {name} placeholder in a --title-format template.
+var titlePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// titleFields are the placeholders a --title-format template may use.
+var titleFields = map[string]bool{"{remaining}": true, "{percent}": true, "{label}": true}
+
+// validateTitleFormat reports the first unknown placeholder in format.
+func validateTitleFormat(format string) error {
+	for _, p := range titlePlaceholder.FindAllString(format, -1) {
+		if !titleFields[p] {
+			return fmt.Errorf("unknown --title-format placeholder %s (use {remaining}, {percent} or {label})", p)
+		}
+	}
+	return nil
+}
+
+// windowTitle keeps the timer in the terminal title (--title-format).
+// It is shared by pointer so the last title set survives model copies.
+type windowTitle struct {
+	format string
+	last   string
+}
+
+// set renders the template from m and returns the command setting it as the
+// title, or nil when the title has not changed.
+func (t *windowTitle) set(m model) tea.Cmd {
+	title := strings.NewReplacer(
+		"{remaining}", localizeDigits(m.timerText()),
+		"{percent}", localizeDigits(strconv.Itoa(int(m.progress()*100))),
+		"{label}", m.label,
+	).Replace(t.format)
+	title = strings.TrimSpace(title) // An empty {label} can leave stray spaces
+	if title == t.last {
+		return nil
+	}
+	t.last = title
+	return tea.SetWindowTitle(title)
+}
+
+// clear blanks the terminal title once the program has exited.
+func (t *windowTitle) clear() {
+	fmt.Fprint(tuiOutput, "\x1b]2;\a")
+}
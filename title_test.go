@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWindowTitleSetsOnChange(t *testing.T) {
+	m := newTestModel(25*time.Minute, 10*time.Minute)
+	m.label = "Write"
+	m.title = &windowTitle{format: "{remaining} {label}"}
+
+	cmd := m.title.set(m)
+	if cmd == nil {
+		t.Fatal("set returned no command for a new title")
+	}
+	// The title goes out through Bubble Tea's own message, never written directly
+	if msg := cmd(); fmt.Sprintf("%T %v", msg, msg) != "tea.setWindowTitleMsg 15:00 Write" {
+		t.Errorf("set sent %T %v, want tea.SetWindowTitle(\"15:00 Write\")", msg, msg)
+	}
+	if cmd := m.title.set(m); cmd != nil {
+		t.Error("set returned a command for an unchanged title")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "os/exec"
+
+// tmuxOption is the tmux user option holding the timer, for use as #{@gopomotime} in status-right.
+const tmuxOption = "@gopomotime"
+
+// tmuxStatus mirrors the displayed timer into a tmux user option (--tmux).
+// It is shared by pointer so the last value written survives model copies.
+// tmux runs on a goroutine of its own, so a slow tmux never holds up Update.
+type tmuxStatus struct {
+	last    string
+	pending chan string   // The next text to write; holds only the latest
+	done    chan struct{} // Closed when the writer has finished
+}
+
+// newTmuxStatus starts the writer goroutine.
+func newTmuxStatus() *tmuxStatus {
+	t := &tmuxStatus{pending: make(chan string, 1), done: make(chan struct{})}
+	go t.write()
+	return t
+}
+
+// write sets the tmux option to each text handed over by set.
+// Errors (e.g. tmux not installed) are ignored.
+func (t *tmuxStatus) write() {
+	defer close(t.done)
+	for text := range t.pending {
+		_ = exec.Command("tmux", "set-option", "-g", tmuxOption, text).Run()
+	}
+}
+
+// set queues the text for tmux, only when it has changed. A text still
+// waiting from before is replaced, so updates never pile up behind tmux.
+func (t *tmuxStatus) set(text string) {
+	if text == t.last {
+		return
+	}
+	t.last = text
+	select {
+	case <-t.pending:
+	default:
+	}
+	t.pending <- text // set is the only sender, so there is room now
+}
+
+// clear stops the writer and removes the tmux option on exit.
+func (t *tmuxStatus) clear() {
+	close(t.pending)
+	<-t.done
+	_ = exec.Command("tmux", "set-option", "-gu", tmuxOption).Run()
+}
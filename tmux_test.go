@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTmux puts a tmux on PATH that sleeps for delay and then appends its
+// arguments to a log, returning the log's path.
+func fakeTmux(t *testing.T, delay string) string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\nsleep " + delay + "\necho \"$*\" >> " + log + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestTmuxStatusDoesNotBlock(t *testing.T) {
+	log := fakeTmux(t, "0.2")
+	s := newTmuxStatus()
+
+	start := time.Now()
+	for _, text := range []string{"24:59", "24:58", "24:57", "24:57"} {
+		s.set(text)
+	}
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Errorf("set took %v with a slow tmux, want it not to wait for tmux", took)
+	}
+	s.clear()
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if last := calls[len(calls)-1]; last != "set-option -gu "+tmuxOption {
+		t.Errorf("last tmux call = %q, want the option removed", last)
+	}
+	if set := calls[len(calls)-2]; set != "set-option -g "+tmuxOption+" 24:57" {
+		t.Errorf("last value written = %q, want the latest text", set)
+	}
+	if len(calls) > 4 {
+		t.Errorf("tmux ran %d times, want updates waiting behind a slow tmux to be dropped", len(calls))
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxTrendDays keeps the --trend line, e.g. "▂▅ ▇ 25d", within the donut width.
+const maxTrendDays = width - 4
+
+// sparkBlocks are the sparkline heights, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// dailyFocus totals the time logged on each of the last days days, ending
+// today, oldest first.
+func dailyFocus(entries []historyEntry, days int, now time.Time) []time.Duration {
+	totals := make([]time.Duration, days)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, e := range entries {
+		end := e.End.In(now.Location())
+		day := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, now.Location())
+		ago := int(today.Sub(day).Hours()+12) / 24 // Rounded, so DST days still count as one
+		if ago >= 0 && ago < days {
+			totals[days-1-ago] += time.Duration(e.ElapsedSeconds+e.OvertimeSeconds) * time.Second
+		}
+	}
+	return totals
+}
+
+// sparkline draws values scaled to the largest, leaving days with nothing
+// logged blank so they stand apart from short ones.
+func sparkline(values []time.Duration) string {
+	var most time.Duration
+	for _, v := range values {
+		most = max(most, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[int(v*time.Duration(len(sparkBlocks)-1)/most)])
+	}
+	return b.String()
+}
+
+// updateTrend recounts the --trend totals from the history file, which now
+// includes the session just finished.
+func (m *model) updateTrend() {
+	if m.opts.trendDays == 0 || m.opts.historyPath == "" {
+		return
+	}
+	if entries, err := readHistory(m.opts.historyPath); err == nil {
+		m.trend = dailyFocus(entries, m.opts.trendDays, time.Now())
+	}
+}
+
+// trendLines returns the --trend sparkline for the finished screen, if
+// anything was logged in the period.
+func (m model) trendLines() []string {
+	var total time.Duration
+	for _, d := range m.trend {
+		total += d
+	}
+	if total == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %dd", sparkline(m.trend), len(m.trend))}
+}